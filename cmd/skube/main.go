@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"time"
 
 	"github.com/geminal/skube/internal/aiparser"
 	"github.com/geminal/skube/internal/cluster"
@@ -112,8 +113,15 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Check if patterns cache is stale and auto-refresh
-	if config.IsClusterPatternsCacheStale() {
+	// Check if patterns cache is stale and auto-refresh. A learned context lists
+	// the kinds whose TTL expired while the command runs on the cached patterns;
+	// a new one is learned in full first.
+	waitForRefresh := func() {}
+	if patterns, err := config.LoadClusterPatterns(); err == nil && !patterns.IsEmpty() {
+		if kinds := patterns.StaleKinds(time.Now()); len(kinds) > 0 {
+			waitForRefresh = refreshInBackground(patterns, kinds)
+		}
+	} else {
 		// Get current context for display
 		currentContext, _ := config.GetCurrentKubeContext()
 		if currentContext != "" {
@@ -132,6 +140,26 @@ func main() {
 		}
 	}
 
+	// Check for explain-parse command (show how a sentence is understood)
+	if os.Args[1] == "explain-parse" {
+		if err := executor.ExplainParse(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%sError: %v%s\n", config.ColorRed, err, config.ColorReset)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for macros command (list configured macros)
+	if os.Args[1] == "macros" {
+		macros, err := config.LoadMacros()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%sError loading macros: %v%s\n", config.ColorRed, err, config.ColorReset)
+			os.Exit(1)
+		}
+		help.PrintMacros(macros)
+		os.Exit(0)
+	}
+
 	// Check for model command
 	if os.Args[1] == "model" {
 		cfg, err := config.LoadAIConfig()
@@ -179,28 +207,91 @@ func main() {
 
 	args := os.Args[1:]
 
-	var ctx *parser.Context
+	var steps []*parser.Context
 	if aiparser.HasAIFlag(args) {
 		args = aiparser.StripAIFlag(args)
 		aiParser, err := aiparser.NewAIParser()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s⚠️  AI Setup Error: %v%s\n", config.ColorYellow, err, config.ColorReset)
 			fmt.Fprintf(os.Stderr, "%sFalling back to regular parser...%s\n\n", config.ColorYellow, config.ColorReset)
-			ctx = parser.ParseNaturalLanguage(args)
+			steps = parser.ParseSequence(args)
 		} else {
-			ctx, err = aiParser.Parse(args)
+			ctx, err := aiParser.Parse(args)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "%s⚠️  AI Parse Error: %v%s\n", config.ColorYellow, err, config.ColorReset)
 				fmt.Fprintf(os.Stderr, "%sFalling back to regular parser...%s\n\n", config.ColorYellow, config.ColorReset)
-				ctx = parser.ParseNaturalLanguage(args)
+				steps = parser.ParseSequence(args)
+			} else {
+				steps = []*parser.Context{ctx}
 			}
 		}
+	} else if macro, ok := lookupMacro(args[0]); ok {
+		var err error
+		steps, err = parser.ExpandMacro(args[0], macro, args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%sError: %v%s\n", config.ColorRed, err, config.ColorReset)
+			os.Exit(1)
+		}
+		fmt.Printf("%s🧩 Running macro %s (%d steps)%s\n", config.ColorCyan, args[0], len(steps), config.ColorReset)
 	} else {
-		ctx = parser.ParseNaturalLanguage(args)
+		steps = parser.ParseSequence(args)
 	}
 
-	if err := executor.ExecuteCommand(ctx); err != nil {
+	err := executor.ExecuteSequence(steps)
+	waitForRefresh()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "%sError: %v%s\n", config.ColorRed, err, config.ColorReset)
 		os.Exit(1)
 	}
+
+	// Remember the target so the next command in this terminal can say "it"
+	rememberTarget(steps)
+}
+
+// rememberTarget saves the target of the last step that has one for this
+// terminal session. It runs only after the steps did, so parsing a command
+// that fails or never runs leaves the memory alone.
+func rememberTarget(steps []*parser.Context) {
+	for i := len(steps) - 1; i >= 0; i-- {
+		target := steps[i].SessionTarget()
+		if target == nil {
+			continue
+		}
+		if err := config.SaveSessionTarget(target); err != nil {
+			fmt.Fprintf(os.Stderr, "%s⚠️  Could not remember the target: %v%s\n", config.ColorYellow, err, config.ColorReset)
+		}
+		return
+	}
+}
+
+// refreshWait is how long skube waits after a command for a background refresh
+const refreshWait = 2 * time.Second
+
+// refreshInBackground refreshes the stale kinds of patterns and saves them
+// without holding up the command. The returned function waits up to refreshWait
+// for the refresh to finish; a slower one is cut off when skube exits, keeping
+// the kinds saved so far, and the rest stay stale for the next run to refresh.
+func refreshInBackground(patterns *config.ClusterPatterns, kinds []string) func() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cluster.RefreshAndSave(patterns, kinds)
+	}()
+
+	return func() {
+		select {
+		case <-done:
+		case <-time.After(refreshWait):
+		}
+	}
+}
+
+// lookupMacro returns the macro configured under name, if any
+func lookupMacro(name string) (*config.Macro, bool) {
+	macros, err := config.LoadMacros()
+	if err != nil {
+		return nil, false
+	}
+	macro, ok := macros[name]
+	return macro, ok && macro != nil
 }
@@ -156,30 +156,63 @@ func parseJSONToContext(jsonStr string) (*parser.Context, error) {
 	if v, ok := raw["namespace"].(string); ok {
 		ctx.Namespace = v
 	}
+	if v, ok := raw["allNamespaces"].(bool); ok {
+		ctx.AllNamespaces = v
+	}
 	if v, ok := raw["appName"].(string); ok {
 		ctx.AppName = v
 	}
 	if v, ok := raw["podName"].(string); ok {
 		ctx.PodName = v
 	}
+	if v, ok := raw["podPick"].(string); ok {
+		ctx.PodPick = v
+	}
+	if v, ok := raw["podIndex"].(float64); ok {
+		ctx.PodIndex = int(v)
+	}
+	if v, ok := raw["node"].(string); ok {
+		ctx.Node = v
+	}
 	if v, ok := raw["serviceName"].(string); ok {
 		ctx.ServiceName = v
 	}
 	if v, ok := raw["deploymentName"].(string); ok {
 		ctx.DeploymentName = v
 	}
+	if v, ok := raw["workloadKind"].(string); ok && v != parser.KwDeployment {
+		ctx.WorkloadKind = v
+	}
 	if v, ok := raw["resourceType"].(string); ok {
 		ctx.ResourceType = v
 	}
 	if v, ok := raw["resourceName"].(string); ok {
 		ctx.ResourceName = v
 	}
+	if v, ok := raw["container"].(string); ok {
+		ctx.Container = v
+	}
+	if v, ok := raw["allContainers"].(bool); ok {
+		ctx.AllContainers = v
+	}
+	if v, ok := raw["selector"].(string); ok {
+		ctx.Selector = v
+	}
+	if v, ok := raw["podStatus"].(string); ok {
+		ctx.PodStatus = v
+	}
+	if v, ok := raw["minRestarts"].(float64); ok {
+		ctx.MinRestarts = int(v)
+	}
 	if v, ok := raw["port"].(string); ok {
 		ctx.Port = v
 	}
 	if v, ok := raw["replicas"].(string); ok {
 		ctx.Replicas = v
 	}
+	if v, ok := raw["scaleOp"].(string); ok {
+		ctx.ScaleOp = v
+	}
 	if v, ok := raw["follow"].(bool); ok {
 		ctx.Follow = v
 	}
@@ -189,9 +222,28 @@ func parseJSONToContext(jsonStr string) (*parser.Context, error) {
 	if v, ok := raw["searchTerm"].(string); ok {
 		ctx.SearchTerm = v
 	}
+	if v, ok := raw["searchRegex"].(bool); ok {
+		ctx.SearchRegex = v
+	}
+	if v, ok := raw["ignoreCase"].(bool); ok {
+		ctx.IgnoreCase = v
+	}
+	if v, ok := raw["exclusions"].([]interface{}); ok {
+		for _, term := range v {
+			if s, ok := term.(string); ok && s != "" {
+				ctx.Exclusions = append(ctx.Exclusions, s)
+			}
+		}
+	}
 	if v, ok := raw["tailLines"].(float64); ok {
 		ctx.TailLines = int(v)
 	}
+	if v, ok := raw["previous"].(bool); ok {
+		ctx.Previous = v
+	}
+	if v, ok := raw["since"].(string); ok {
+		ctx.Since = v
+	}
 	if v, ok := raw["filePath"].(string); ok {
 		ctx.FilePath = v
 	}
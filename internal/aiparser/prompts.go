@@ -13,18 +13,34 @@ OUTPUT FORMAT (only include fields that are needed):
 {
   "command": "string",
   "namespace": "string",
+  "allNamespaces": boolean,
   "appName": "string",
   "podName": "string",
+  "podPick": "newest|oldest|ready (pick one pod of appName)",
+  "podIndex": number,
+  "node": "string (pick a pod of appName on this node)",
   "serviceName": "string",
   "deploymentName": "string",
+  "workloadKind": "statefulset|daemonset|job|cronjob (kind of deploymentName when it is not a deployment)",
   "resourceType": "string",
   "resourceName": "string",
+  "container": "string",
+  "allContainers": boolean,
+  "selector": "string",
+  "podStatus": "crashing|pending|failed|not-ready|evicted",
+  "minRestarts": number,
   "port": "string",
   "replicas": "string",
+  "scaleOp": "up|down|double|half|back (relative scaling; replicas is the amount for up/down)",
   "follow": boolean,
   "prefix": boolean,
   "searchTerm": "string",
+  "searchRegex": boolean,
+  "ignoreCase": boolean,
+  "exclusions": ["string (namespaces for listings, text for logs, event types for events)"],
   "tailLines": number,
+  "previous": boolean,
+  "since": "string (kubectl duration, e.g. 15m, 1h)",
   "filePath": "string"
 }
 
@@ -33,10 +49,12 @@ COMMANDS (what action to take):
 - scale (change replicas), forward (port forward), describe (show details)
 - pods, deployments, services, namespaces (list resources - use these instead of "get")
 - status, events, apply, delete, edit, rollback, nodes, configmaps, secrets, ingresses, pvcs
+- statefulsets, daemonsets, jobs, cronjobs (list workloads)
+- resources (list any other resource type, e.g. custom resources; set resourceType to its plural)
 - IMPORTANT: There is NO "get" command. Use the resource type directly (pods, services, deployments, etc.)
 
 RESOURCE TYPES:
-- pod, deployment, service, namespace, node, configmap, secret, ingress, persistentvolumeclaim
+- pod, deployment, statefulset, daemonset, job, cronjob, service, namespace, node, configmap, secret, ingress, persistentvolumeclaim
 
 NAMESPACES (common examples):
 - Kubernetes namespaces vary by cluster (e.g., default, kube-system, or custom namespaces)
@@ -78,6 +96,9 @@ Output: {"command":"pods","namespace":"namespace-c"}
 Input: "get pods in namespace-c"
 Output: {"command":"pods","namespace":"namespace-c"}
 
+Input: "pods with label tier=backend in prod"
+Output: {"command":"pods","selector":"tier=backend","namespace":"prod"}
+
 Input: "get services in qa"
 Output: {"command":"services","namespace":"qa"}
 
@@ -99,11 +120,17 @@ Output: {"command":"scale","deploymentName":"api","replicas":"5","namespace":"ns
 Input: "shell into pod api-xyz-123"
 Output: {"command":"shell","podName":"api-xyz-123"}
 
+Input: "logs from the second api pod"
+Output: {"command":"logs","appName":"api","podIndex":2}
+
 Input: "in namespace-a forward port 8080 from service backend"
 Output: {"command":"forward","serviceName":"backend","port":"8080","namespace":"namespace-a"}
 
 Input: "yo, show me what pods are crashing in namespace-a"
-Output: {"command":"pods","namespace":"namespace-a"}
+Output: {"command":"pods","podStatus":"crashing","namespace":"namespace-a"}
+
+Input: "pods restarted more than 5 times in qa"
+Output: {"command":"pods","minRestarts":6,"namespace":"qa"}
 
 Input: "in namsepace-a get depoyments"
 Output: {"command":"deployments","namespace":"namespace-a"}
@@ -43,51 +43,28 @@ func LearnClusterPatterns(showProgress bool) (*config.ClusterPatterns, error) {
 		Patterns:           []string{},
 		MultiWordResources: []string{},
 		AppLabels:          make(map[string]string),
+		AppLabelKeys:       make(map[string]string),
+		Containers:         make(map[string][]string),
 	}
 
-	// Fetch namespaces
-	namespaces, err := getNamespacesWithContext(ctx)
-	if err == nil {
-		patterns.Namespaces = namespaces
+	// List every kind of resource, then derive the naming patterns
+	for _, kind := range config.PatternKinds {
+		refreshKind(ctx, patterns, kind)
 	}
-
-	// Fetch deployments from all namespaces
-	deployments, err := getDeploymentsAllNamespaces(ctx)
-	if err == nil {
-		patterns.Deployments = deployments
-		patterns.MultiWordResources = append(patterns.MultiWordResources, extractMultiWordResources(deployments)...)
-	}
-
-	// Fetch services from all namespaces
-	services, err := getServicesAllNamespaces(ctx)
-	if err == nil {
-		patterns.Services = services
-		patterns.MultiWordResources = append(patterns.MultiWordResources, extractMultiWordResources(services)...)
-	}
-
-	// Fetch pods and their app labels
-	pods, appLabels, err := getPodsWithAppLabels(ctx)
-	if err == nil {
-		patterns.Pods = pods
-		patterns.AppLabels = appLabels
-		patterns.CommonApps = extractCommonApps(appLabels)
-	}
-
-	// Detect naming patterns
-	patterns.Patterns = detectNamingPatterns(patterns)
-
-	// Detect the dominant naming convention
-	patterns.NamingConvention = detectNamingConvention(patterns)
-
-	// Remove duplicates from MultiWordResources
-	patterns.MultiWordResources = uniqueStrings(patterns.MultiWordResources)
+	derivePatterns(patterns)
 
 	if showProgress {
-		fmt.Printf("Done! Found %d namespaces, %d deployments, %d services, %d apps.\n",
+		workloads := 0
+		for _, names := range patterns.Workloads {
+			workloads += len(names)
+		}
+		fmt.Printf("Done! Found %d namespaces, %d deployments, %d other workloads, %d services, %d apps, %d resource types.\n",
 			len(patterns.Namespaces),
 			len(patterns.Deployments),
+			workloads,
 			len(patterns.Services),
-			len(patterns.CommonApps))
+			len(patterns.CommonApps),
+			len(patterns.APIResources))
 
 		// Show detected naming convention
 		if patterns.NamingConvention != "" {
@@ -102,77 +79,215 @@ func LearnClusterPatterns(showProgress bool) (*config.ClusterPatterns, error) {
 	return patterns, nil
 }
 
-// getNamespacesWithContext fetches all namespaces
-func getNamespacesWithContext(ctx context.Context) ([]string, error) {
-	return getNamespaces(ctx)
+// workloadResources maps the workload kinds learned besides deployments to their kubectl resource
+var workloadResources = map[string]string{
+	"statefulset": "statefulsets",
+	"daemonset":   "daemonsets",
+	"job":         "jobs",
+	"cronjob":     "cronjobs",
 }
 
-// getDeploymentsAllNamespaces fetches all deployments from all namespaces
-func getDeploymentsAllNamespaces(ctx context.Context) ([]string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "deployments", "--all-namespaces", "-o", "jsonpath={range .items[*]}{.metadata.namespace}/{.metadata.name}{'\\n'}{end}")
-	output, err := cmd.Output()
+// listResources fetches "namespace/name", or "name" for cluster-scoped resources,
+// for all resources of a type, with a fingerprint of their resourceVersions
+func listResources(ctx context.Context, resource string, namespaced bool) ([]string, string, error) {
+	args := []string{"get", resource, "-o", "jsonpath={range .items[*]}{.metadata.name}{'|'}{.metadata.resourceVersion}{'\\n'}{end}"}
+	if namespaced {
+		args = []string{"get", resource, "--all-namespaces", "-o", "jsonpath={range .items[*]}{.metadata.namespace}/{.metadata.name}{'|'}{.metadata.resourceVersion}{'\\n'}{end}"}
+	}
+	output, err := exec.CommandContext(ctx, "kubectl", args...).Output()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var deployments []string
-	for _, line := range lines {
-		if line != "" {
-			deployments = append(deployments, line)
+	names, versions := parseResourceVersions(string(output))
+	return names, fingerprint(versions), nil
+}
+
+// parseResourceVersions reads "name|resourceVersion" lines into the names and their versions
+func parseResourceVersions(output string) ([]string, []string) {
+	var names, versions []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
 		}
+		name, _, _ := strings.Cut(line, "|")
+		names = append(names, name)
+		versions = append(versions, line)
 	}
-	return deployments, nil
+	return names, versions
 }
 
-// getServicesAllNamespaces fetches all services from all namespaces
-func getServicesAllNamespaces(ctx context.Context) ([]string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "services", "--all-namespaces", "-o", "jsonpath={range .items[*]}{.metadata.namespace}/{.metadata.name}{'\\n'}{end}")
+// getAPIResources lists the resource types the cluster serves that can be listed
+func getAPIResources(ctx context.Context) ([]config.APIResource, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", "api-resources", "--verbs=list")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
+	return parseAPIResources(string(output)), nil
+}
+
+// parseAPIResources reads the table printed by kubectl api-resources. Columns are
+// located by their header, since the SHORTNAMES column is blank for most types.
+func parseAPIResources(output string) []config.APIResource {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+
+	header := lines[0]
+	columns := []string{"NAME", "SHORTNAMES", "APIVERSION", "NAMESPACED", "KIND"}
+	starts := make([]int, len(columns))
+	for i, column := range columns {
+		starts[i] = strings.Index(header, column)
+		if starts[i] < 0 || (i > 0 && starts[i] <= starts[i-1]) {
+			return nil
+		}
+	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var services []string
-	for _, line := range lines {
-		if line != "" {
-			services = append(services, line)
+	field := func(line string, i int) string {
+		if starts[i] >= len(line) {
+			return ""
 		}
+		end := len(line)
+		if i+1 < len(starts) && starts[i+1] < end {
+			end = starts[i+1]
+		}
+		return strings.TrimSpace(line[starts[i]:end])
 	}
-	return services, nil
+
+	var resources []config.APIResource
+	for _, line := range lines[1:] {
+		name := field(line, 0)
+		if name == "" {
+			continue
+		}
+		resource := config.APIResource{
+			Name:       name,
+			Kind:       field(line, 4),
+			Namespaced: field(line, 3) == "true",
+		}
+		if shortNames := field(line, 1); shortNames != "" {
+			resource.ShortNames = strings.Split(shortNames, ",")
+		}
+		if apiVersion := field(line, 2); strings.Contains(apiVersion, "/") {
+			resource.Group = apiVersion[:strings.LastIndex(apiVersion, "/")]
+		}
+		resources = append(resources, resource)
+	}
+	return resources
 }
 
-// getPodsWithAppLabels fetches all pods and their app labels
-func getPodsWithAppLabels(ctx context.Context) ([]string, map[string]string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "pods", "--all-namespaces", "-o", "jsonpath={range .items[*]}{.metadata.namespace}/{.metadata.name}{'|'}{.metadata.labels.app}{'\\n'}{end}")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, nil, err
+// listPods fetches all pods with their container names and app labels, with one
+// label value per key in labelKeys
+func listPods(ctx context.Context, labelKeys []string) (string, error) {
+	jsonpath := "{range .items[*]}{.metadata.namespace}/{.metadata.name}{'|'}{range .spec.containers[*]}{.name}{','}{end}"
+	for _, key := range labelKeys {
+		jsonpath += "{'|'}{.metadata.labels." + jsonpathKey(key) + "}"
 	}
+	jsonpath += "{'\\n'}{end}"
+
+	output, err := exec.CommandContext(ctx, "kubectl", "get", "pods", "--all-namespaces", "-o", "jsonpath="+jsonpath).Output()
+	return string(output), err
+}
+
+// jsonpathKey escapes the dots of a label key such as app.kubernetes.io/name for kubectl jsonpath
+func jsonpathKey(key string) string {
+	return strings.ReplaceAll(key, ".", "\\.")
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+// parsePods reads "namespace/pod|containers,|value|value..." lines, with one value
+// per label key in priority order. A pod's app is the value of the first key it
+// carries; the key each app uses is recorded in appLabelKeys as "namespace/app" -> key.
+func parsePods(output string, labelKeys []string, appLabelKeys map[string]string) ([]string, map[string]string, map[string][]string) {
 	var pods []string
 	appLabels := make(map[string]string)
+	containers := make(map[string][]string)
 
-	for _, line := range lines {
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
 		if line == "" {
 			continue
 		}
 
 		parts := strings.Split(line, "|")
-		if len(parts) >= 1 {
-			podName := parts[0]
-			pods = append(pods, podName)
+		podName := parts[0]
+		pods = append(pods, podName)
+
+		// Store container names (sidecars included)
+		if len(parts) >= 2 {
+			for _, name := range strings.Split(parts[1], ",") {
+				if name != "" {
+					containers[podName] = append(containers[podName], name)
+				}
+			}
+		}
 
-			// Store app label if it exists
-			if len(parts) == 2 && parts[1] != "" && parts[1] != "<no value>" {
-				appLabels[podName] = parts[1]
+		// Store the app label under the most preferred key the pod carries
+		for i, key := range labelKeys {
+			if i+2 >= len(parts) {
+				break
 			}
+			app := parts[i+2]
+			if app == "" || app == "<no value>" {
+				continue
+			}
+			appLabels[podName] = app
+			namespace, _, _ := strings.Cut(podName, "/")
+			appLabelKeys[namespace+"/"+app] = key
+			break
 		}
 	}
 
-	return pods, appLabels, nil
+	return pods, appLabels, containers
+}
+
+// getOwnersAllNamespaces fetches the first owner reference of every resource of a
+// type, as "namespace/name" -> "Kind/name". Resources without owners are left out.
+func getOwnersAllNamespaces(ctx context.Context, resource string) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", "get", resource, "--all-namespaces", "-o", "jsonpath={range .items[*]}{.metadata.namespace}/{.metadata.name}{'|'}{range .metadata.ownerReferences[*]}{.kind}/{.name}{','}{end}{'\\n'}{end}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, references, _ := strings.Cut(line, "|")
+		if owner, _, _ := strings.Cut(references, ","); owner != "" {
+			owners[name] = owner
+		}
+	}
+	return owners, nil
+}
+
+// ownerKinds maps the owner kinds a pod can be traced to onto workload kinds
+var ownerKinds = map[string]string{
+	"Deployment":  "deployment",
+	"StatefulSet": "statefulset",
+	"DaemonSet":   "daemonset",
+	"Job":         "job",
+}
+
+// resolveOwners maps pods to the workload that owns them, following
+// pod -> ReplicaSet -> Deployment. Pods owned by anything else, or by a
+// ReplicaSet without a Deployment, are left out.
+func resolveOwners(podOwners map[string]string, replicaSetOwners map[string]string) map[string]string {
+	owners := make(map[string]string)
+	for pod, owner := range podOwners {
+		namespace, _, _ := strings.Cut(pod, "/")
+		kind, name, _ := strings.Cut(owner, "/")
+		if kind == "ReplicaSet" {
+			owner = replicaSetOwners[namespace+"/"+name]
+			kind, name, _ = strings.Cut(owner, "/")
+			if kind != "Deployment" {
+				continue
+			}
+		}
+		if workload, ok := ownerKinds[kind]; ok && name != "" {
+			owners[pod] = workload + "/" + name
+		}
+	}
+	return owners
 }
 
 // extractMultiWordResources identifies resources with hyphens (multi-word names)
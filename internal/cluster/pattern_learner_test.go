@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestParseAPIResources(t *testing.T) {
+	output := `NAME                SHORTNAMES   APIVERSION                     NAMESPACED   KIND
+configmaps          cm           v1                             true         ConfigMap
+nodes               no           v1                             false        Node
+deployments         deploy       apps/v1                        true         Deployment
+rollouts            ro           argoproj.io/v1alpha1           true         Rollout
+certificates        cert,certs   cert-manager.io/v1             true         Certificate
+clusterissuers                   cert-manager.io/v1             false        ClusterIssuer
+virtualservices     vs           networking.istio.io/v1beta1    true         VirtualService
+`
+
+	expected := []config.APIResource{
+		{Name: "configmaps", ShortNames: []string{"cm"}, Kind: "ConfigMap", Namespaced: true},
+		{Name: "nodes", ShortNames: []string{"no"}, Kind: "Node"},
+		{Name: "deployments", ShortNames: []string{"deploy"}, Group: "apps", Kind: "Deployment", Namespaced: true},
+		{Name: "rollouts", ShortNames: []string{"ro"}, Group: "argoproj.io", Kind: "Rollout", Namespaced: true},
+		{Name: "certificates", ShortNames: []string{"cert", "certs"}, Group: "cert-manager.io", Kind: "Certificate", Namespaced: true},
+		{Name: "clusterissuers", Group: "cert-manager.io", Kind: "ClusterIssuer"},
+		{Name: "virtualservices", ShortNames: []string{"vs"}, Group: "networking.istio.io", Kind: "VirtualService", Namespaced: true},
+	}
+
+	got := parseAPIResources(output)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseAPIResources() =\n%+v\nwant\n%+v", got, expected)
+	}
+
+	if got := parseAPIResources("error: the server doesn't have a resource type\n"); got != nil {
+		t.Errorf("expected nil for output without a table, got %+v", got)
+	}
+}
+
+func TestResolveOwners(t *testing.T) {
+	podOwners := map[string]string{
+		"prod/api-7d9f8b6c5-x2x4z": "ReplicaSet/api-7d9f8b6c5",
+		"prod/db-0":                "StatefulSet/db",
+		"prod/agent-k8s2p":         "DaemonSet/agent",
+		"prod/migrate-9xk2q":       "Job/migrate",
+		"prod/orphan-5f6d7-abcde":  "ReplicaSet/orphan-5f6d7",
+		"prod/rollout-6c8d-qwert":  "ReplicaSet/rollout-6c8d",
+		"prod/static-node-a":       "Node/node-a",
+	}
+	replicaSetOwners := map[string]string{
+		"prod/api-7d9f8b6c5": "Deployment/api",
+		"prod/rollout-6c8d":  "Rollout/rollout",
+	}
+
+	expected := map[string]string{
+		"prod/api-7d9f8b6c5-x2x4z": "deployment/api",
+		"prod/db-0":                "statefulset/db",
+		"prod/agent-k8s2p":         "daemonset/agent",
+		"prod/migrate-9xk2q":       "job/migrate",
+	}
+	if got := resolveOwners(podOwners, replicaSetOwners); !reflect.DeepEqual(got, expected) {
+		t.Errorf("resolveOwners() = %v, want %v", got, expected)
+	}
+}
+
+func TestParsePods(t *testing.T) {
+	output := `prod/api-7d9f-x2x4z|api,istio-proxy,|api||
+kube-system/coredns-5c6d-qwrtz|coredns,|||coredns
+prod/web-6b7c-mnbvc|web,||web-frontend|web
+prod/static|nginx,|<no value>||
+`
+	keys := []string{"app", "app.kubernetes.io/name", "k8s-app"}
+	appLabelKeys := map[string]string{}
+
+	pods, appLabels, containers := parsePods(output, keys, appLabelKeys)
+
+	if expected := []string{"prod/api-7d9f-x2x4z", "kube-system/coredns-5c6d-qwrtz", "prod/web-6b7c-mnbvc", "prod/static"}; !reflect.DeepEqual(pods, expected) {
+		t.Errorf("pods = %v, want %v", pods, expected)
+	}
+	expectedApps := map[string]string{
+		"prod/api-7d9f-x2x4z":            "api",
+		"kube-system/coredns-5c6d-qwrtz": "coredns",
+		"prod/web-6b7c-mnbvc":            "web-frontend",
+	}
+	if !reflect.DeepEqual(appLabels, expectedApps) {
+		t.Errorf("appLabels = %v, want %v", appLabels, expectedApps)
+	}
+	expectedKeys := map[string]string{
+		"prod/api":            "app",
+		"kube-system/coredns": "k8s-app",
+		"prod/web-frontend":   "app.kubernetes.io/name",
+	}
+	if !reflect.DeepEqual(appLabelKeys, expectedKeys) {
+		t.Errorf("appLabelKeys = %v, want %v", appLabelKeys, expectedKeys)
+	}
+	if expected := []string{"api", "istio-proxy"}; !reflect.DeepEqual(containers["prod/api-7d9f-x2x4z"], expected) {
+		t.Errorf("containers = %v, want %v", containers["prod/api-7d9f-x2x4z"], expected)
+	}
+}
+
+func TestJsonpathKey(t *testing.T) {
+	if got := jsonpathKey("app.kubernetes.io/name"); got != `app\.kubernetes\.io/name` {
+		t.Errorf("jsonpathKey() = %q", got)
+	}
+}
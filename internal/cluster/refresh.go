@@ -0,0 +1,181 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// refreshTimeout bounds an incremental refresh, which runs alongside the command
+const refreshTimeout = 10 * time.Second
+
+// RefreshClusterPatterns lists the given kinds of patterns again, merges the ones
+// whose resourceVersions changed into patterns and derives the naming patterns
+// again if any did. It returns the kinds that changed. Kinds not listed keep their
+// patterns, so a refresh costs only the listings of the stale kinds.
+func RefreshClusterPatterns(patterns *config.ClusterPatterns, kinds []string) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	var changed []string
+	for _, kind := range kinds {
+		if refreshKind(ctx, patterns, kind) {
+			changed = append(changed, kind)
+		}
+	}
+	if len(changed) > 0 {
+		derivePatterns(patterns)
+	}
+	return changed
+}
+
+// savePatterns saves refreshed patterns. It is a variable to allow mocking in tests.
+var savePatterns = config.SaveClusterPatterns
+
+// RefreshAndSave refreshes the given kinds one at a time like RefreshClusterPatterns
+// and saves patterns after each, so that a refresh cut off part way, as when skube
+// exits after the command, keeps the kinds it finished. The kinds it did not reach
+// stay stale for the next run.
+func RefreshAndSave(patterns *config.ClusterPatterns, kinds []string) error {
+	for _, kind := range kinds {
+		RefreshClusterPatterns(patterns, []string{kind})
+		if err := savePatterns(patterns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshKind lists one kind of pattern and stores it in patterns when its
+// fingerprint changed, reporting whether it did. A kind that cannot be listed
+// keeps its previous patterns until its TTL expires again.
+func refreshKind(ctx context.Context, patterns *config.ClusterPatterns, kind string) bool {
+	if patterns.Refreshed == nil {
+		patterns.Refreshed = make(map[string]config.KindRefresh)
+	}
+	previous := patterns.Refreshed[kind]
+
+	fingerprint, apply, err := fetchKind(ctx, patterns, kind)
+	if err != nil {
+		previous.At = time.Now()
+		patterns.Refreshed[kind] = previous
+		return false
+	}
+	patterns.Refreshed[kind] = config.KindRefresh{At: time.Now(), Fingerprint: fingerprint}
+	if fingerprint == previous.Fingerprint {
+		return false
+	}
+	apply()
+	return true
+}
+
+// fetchKind lists one kind of pattern. It returns a fingerprint of the listing and
+// a function that stores the listed patterns, so unchanged kinds are left alone.
+func fetchKind(ctx context.Context, patterns *config.ClusterPatterns, kind string) (string, func(), error) {
+	switch kind {
+	case config.KindNamespaces:
+		names, versions, err := listResources(ctx, "namespaces", false)
+		return versions, func() { patterns.Namespaces = names }, err
+
+	case config.KindDeployments:
+		names, versions, err := listResources(ctx, "deployments", true)
+		return versions, func() { patterns.Deployments = names }, err
+
+	case config.KindServices:
+		names, versions, err := listResources(ctx, "services", true)
+		return versions, func() { patterns.Services = names }, err
+
+	case config.KindWorkloads:
+		// Fetch the other workload kinds so names can pick their kind
+		kinds := make([]string, 0, len(workloadResources))
+		for kind := range workloadResources {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		workloads := make(map[string][]string)
+		var versions []string
+		var lastErr error
+		for _, kind := range kinds {
+			names, kindVersions, err := listResources(ctx, workloadResources[kind], true)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if len(names) > 0 {
+				workloads[kind] = names
+			}
+			versions = append(versions, kindVersions)
+		}
+		if len(versions) == 0 {
+			return "", nil, lastErr
+		}
+		return fingerprint(versions), func() { patterns.Workloads = workloads }, nil
+
+	case config.KindAPIResources:
+		// Discover resource types, including custom resources, for the parser vocabulary
+		apiResources, err := getAPIResources(ctx)
+		return fingerprint(apiResources), func() { patterns.APIResources = apiResources }, err
+
+	case config.KindPods:
+		// Pods change resourceVersion with every status update, so they are compared
+		// by what skube learns from them: names, containers and app labels
+		labelKeys := config.LoadAppLabelKeys(patterns.KubeContext)
+		output, err := listPods(ctx, labelKeys)
+		return fingerprint(output), func() {
+			appLabelKeys := make(map[string]string)
+			patterns.Pods, patterns.AppLabels, patterns.Containers = parsePods(output, labelKeys, appLabelKeys)
+			patterns.AppLabelKeys = appLabelKeys
+
+			// Follow owner references so stale pod names lead back to their workload
+			if podOwners, err := getOwnersAllNamespaces(ctx, "pods"); err == nil {
+				replicaSetOwners, _ := getOwnersAllNamespaces(ctx, "replicasets")
+				patterns.Owners = resolveOwners(podOwners, replicaSetOwners)
+			}
+		}, err
+	}
+
+	return "", nil, fmt.Errorf("unknown pattern kind %q", kind)
+}
+
+// derivePatterns computes the patterns derived from the listed resources: multi-word
+// names, apps, naming patterns and the naming convention
+func derivePatterns(patterns *config.ClusterPatterns) {
+	multiWord := extractMultiWordResources(patterns.Deployments)
+	multiWord = append(multiWord, extractMultiWordResources(patterns.Services)...)
+	kinds := make([]string, 0, len(patterns.Workloads))
+	for kind := range patterns.Workloads {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		multiWord = append(multiWord, extractMultiWordResources(patterns.Workloads[kind])...)
+	}
+
+	// Remove duplicates from MultiWordResources
+	patterns.MultiWordResources = uniqueStrings(multiWord)
+
+	patterns.CommonApps = extractCommonApps(patterns.AppLabels)
+
+	// Detect naming patterns
+	patterns.Patterns = detectNamingPatterns(patterns)
+
+	// Detect the dominant naming convention
+	patterns.NamingConvention = detectNamingConvention(patterns)
+}
+
+// fingerprint hashes a listing so that it can be compared with the previous one
+func fingerprint(listing any) string {
+	data, err := json.Marshal(listing)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,128 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// useFakeKubectl puts a kubectl on PATH that prints the file named after the
+// resource it gets, e.g. dir/deployments, and fails for anything else
+func useFakeKubectl(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake kubectl is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = "get" ] && [ -f "` + dir + `/$2" ] && exec cat "` + dir + `/$2"
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestRefreshClusterPatterns(t *testing.T) {
+	dir := useFakeKubectl(t)
+	write := func(resource, listing string) {
+		if err := os.WriteFile(filepath.Join(dir, resource), []byte(listing), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	patterns := &config.ClusterPatterns{
+		Namespaces: []string{"prod"},
+		Services:   []string{"prod/web-svc"},
+	}
+	write("deployments", "prod/api|100\nprod/web-app|101\n")
+
+	// Only the listed kinds are refreshed; the others keep their patterns
+	changed := RefreshClusterPatterns(patterns, []string{config.KindDeployments})
+	if !reflect.DeepEqual(changed, []string{config.KindDeployments}) {
+		t.Fatalf("expected deployments to change, got %v", changed)
+	}
+	if expected := []string{"prod/api", "prod/web-app"}; !reflect.DeepEqual(patterns.Deployments, expected) {
+		t.Errorf("expected deployments %v, got %v", expected, patterns.Deployments)
+	}
+	if expected := []string{"web-app", "web-svc"}; !reflect.DeepEqual(patterns.MultiWordResources, expected) {
+		t.Errorf("expected multi-word resources %v, got %v", expected, patterns.MultiWordResources)
+	}
+	if !reflect.DeepEqual(patterns.Services, []string{"prod/web-svc"}) || !reflect.DeepEqual(patterns.Namespaces, []string{"prod"}) {
+		t.Errorf("expected services and namespaces to be kept, got %v and %v", patterns.Services, patterns.Namespaces)
+	}
+
+	// Unchanged resourceVersions leave the patterns alone
+	patterns.Deployments = []string{"prod/api"}
+	if changed := RefreshClusterPatterns(patterns, []string{config.KindDeployments}); changed != nil {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+	if len(patterns.Deployments) != 1 {
+		t.Errorf("expected unchanged deployments not to be stored again, got %v", patterns.Deployments)
+	}
+
+	// A new resourceVersion is merged
+	write("deployments", "prod/api|100\nprod/web-app|105\n")
+	if changed := RefreshClusterPatterns(patterns, []string{config.KindDeployments}); len(changed) != 1 {
+		t.Errorf("expected deployments to change, got %v", changed)
+	}
+
+	// A kind that cannot be listed keeps its patterns and is not retried before its TTL
+	if changed := RefreshClusterPatterns(patterns, []string{config.KindServices}); changed != nil {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+	if !reflect.DeepEqual(patterns.Services, []string{"prod/web-svc"}) {
+		t.Errorf("expected services to be kept, got %v", patterns.Services)
+	}
+	for _, kind := range patterns.StaleKinds(patterns.Refreshed[config.KindServices].At) {
+		if kind == config.KindServices || kind == config.KindDeployments {
+			t.Errorf("expected %s not to be stale right after a refresh", kind)
+		}
+	}
+}
+
+func TestRefreshAndSaveSavesEachKind(t *testing.T) {
+	dir := useFakeKubectl(t)
+	write := func(resource, listing string) {
+		if err := os.WriteFile(filepath.Join(dir, resource), []byte(listing), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("deployments", "prod/api|100\n")
+	write("services", "prod/web-svc|200\n")
+
+	var saved [][]string
+	oldSave := savePatterns
+	savePatterns = func(patterns *config.ClusterPatterns) error {
+		saved = append(saved, append(slices.Clone(patterns.Deployments), patterns.Services...))
+		return nil
+	}
+	defer func() { savePatterns = oldSave }()
+
+	// Each kind is saved as soon as it is refreshed
+	patterns := &config.ClusterPatterns{}
+	if err := RefreshAndSave(patterns, []string{config.KindDeployments, config.KindServices}); err != nil {
+		t.Fatalf("RefreshAndSave() error = %v", err)
+	}
+	expected := [][]string{{"prod/api"}, {"prod/api", "prod/web-svc"}}
+	if !reflect.DeepEqual(saved, expected) {
+		t.Errorf("expected saves %v, got %v", expected, saved)
+	}
+}
+
+func TestParseResourceVersions(t *testing.T) {
+	names, versions := parseResourceVersions("prod/api|100\nprod/web|101\n")
+	if expected := []string{"prod/api", "prod/web"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("names = %v, want %v", names, expected)
+	}
+	if expected := []string{"prod/api|100", "prod/web|101"}; !reflect.DeepEqual(versions, expected) {
+		t.Errorf("versions = %v, want %v", versions, expected)
+	}
+}
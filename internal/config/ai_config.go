@@ -18,6 +18,9 @@ type AIConfig struct {
 	CommonApps   []string          `json:"common_apps,omitempty"`
 	Namespaces   []string          `json:"namespaces,omitempty"`
 	CustomHints  map[string]string `json:"custom_hints,omitempty"`
+	Vocabulary   *Vocabulary       `json:"vocabulary,omitempty"`
+	Macros       map[string]*Macro `json:"macros,omitempty"`
+	Labels       *Labels           `json:"labels,omitempty"`
 }
 
 func GetConfigPath() string {
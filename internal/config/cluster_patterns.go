@@ -7,24 +7,46 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 )
 
 // ClusterPatterns holds learned patterns from the Kubernetes cluster
 type ClusterPatterns struct {
-	KubeContext        string            `json:"kubeContext"`      // Kubernetes context this cache is for
-	ClusterName        string            `json:"clusterName"`      // Cluster name (optional, for display)
-	LastUpdated        time.Time         `json:"lastUpdated"`
-	Namespaces         []string          `json:"namespaces"`
-	CommonApps         []string          `json:"commonApps"`
-	Deployments        []string          `json:"deployments"`
-	Services           []string          `json:"services"`
-	Pods               []string          `json:"pods"`
-	Patterns           []string          `json:"patterns"`
-	MultiWordResources []string          `json:"multiWordResources"`
-	AppLabels          map[string]string `json:"appLabels"`        // pod name -> app label
-	NamingConvention   string            `json:"namingConvention"` // detected naming style: "hyphen", "camelCase", "underscore", "PascalCase", "mixed"
+	KubeContext        string                 `json:"kubeContext"` // Kubernetes context this cache is for
+	ClusterName        string                 `json:"clusterName"` // Cluster name (optional, for display)
+	LastUpdated        time.Time              `json:"lastUpdated"`
+	Namespaces         []string               `json:"namespaces"`
+	CommonApps         []string               `json:"commonApps"`
+	Deployments        []string               `json:"deployments"`
+	Services           []string               `json:"services"`
+	Workloads          map[string][]string    `json:"workloads,omitempty"` // kind ("statefulset", "daemonset", "job", "cronjob") -> "namespace/name"
+	Pods               []string               `json:"pods"`
+	Patterns           []string               `json:"patterns"`
+	MultiWordResources []string               `json:"multiWordResources"`
+	AppLabels          map[string]string      `json:"appLabels"`              // pod name -> app label
+	AppLabelKeys       map[string]string      `json:"appLabelKeys,omitempty"` // "namespace/app" -> label key naming the app, e.g. "app.kubernetes.io/name"
+	Containers         map[string][]string    `json:"containers,omitempty"`   // pod name -> container names
+	Owners             map[string]string      `json:"owners,omitempty"`       // pod name -> owning workload as "kind/name", e.g. "deployment/api"
+	NamingConvention   string                 `json:"namingConvention"`       // detected naming style: "hyphen", "camelCase", "underscore", "PascalCase", "mixed"
+	APIResources       []APIResource          `json:"apiResources,omitempty"` // resource types served by the cluster, including CRDs
+	Refreshed          map[string]KindRefresh `json:"refreshed,omitempty"`    // pattern kind -> when it was last listed
+}
+
+// KindRefresh records when one kind of pattern was last listed from the cluster
+type KindRefresh struct {
+	At          time.Time `json:"at"`
+	Fingerprint string    `json:"fingerprint,omitempty"` // hash of the names and resourceVersions listed
+}
+
+// APIResource is a resource type served by the cluster, as listed by kubectl api-resources
+type APIResource struct {
+	Name       string   `json:"name"` // plural, e.g. "rollouts"
+	ShortNames []string `json:"shortNames,omitempty"`
+	Group      string   `json:"group,omitempty"` // empty for the core group
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
 }
 
 const (
@@ -32,6 +54,30 @@ const (
 	patternsCacheTTL = 24 * time.Hour
 )
 
+// Kinds of patterns that are listed and refreshed separately
+const (
+	KindNamespaces   = "namespaces"
+	KindDeployments  = "deployments"
+	KindServices     = "services"
+	KindWorkloads    = "workloads"    // statefulsets, daemonsets, jobs and cronjobs
+	KindAPIResources = "apiResources" // resource types, including CRDs
+	KindPods         = "pods"         // pods with their app labels, containers and owners
+)
+
+// PatternKinds lists every kind of pattern, in the order they are learned
+var PatternKinds = []string{KindNamespaces, KindDeployments, KindServices, KindWorkloads, KindAPIResources, KindPods}
+
+// patternKindTTLs is how long each kind stays fresh. Deployments change with
+// every release; namespaces and resource types rarely do.
+var patternKindTTLs = map[string]time.Duration{
+	KindNamespaces:   6 * time.Hour,
+	KindDeployments:  15 * time.Minute,
+	KindServices:     time.Hour,
+	KindWorkloads:    time.Hour,
+	KindAPIResources: patternsCacheTTL,
+	KindPods:         patternsCacheTTL,
+}
+
 // GetCurrentKubeContext returns the current kubectl context
 func GetCurrentKubeContext() (string, error) {
 	cmd := exec.Command("kubectl", "config", "current-context")
@@ -203,27 +249,153 @@ func SaveClusterPatterns(patterns *ClusterPatterns) error {
 		return err
 	}
 
-	// Save to context-specific file
+	// Save to context-specific file. Writing a temporary file and renaming it over
+	// the cache means a concurrent skube run reads either the old or the new patterns.
 	safeContext := sanitizeContextName(patterns.KubeContext)
 	filePath := filepath.Join(patternsDir, safeContext+".json")
-	return os.WriteFile(filePath, data, 0644)
+	tmp, err := os.CreateTemp(patternsDir, safeContext+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// ContainersFor returns the container names learned for a pod, or for all pods of an app.
+// Pod and app names are matched within the namespace when one is given.
+func (p *ClusterPatterns) ContainersFor(namespace, app, pod string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for podKey, containers := range p.Containers {
+		parts := strings.SplitN(podKey, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if namespace != "" && parts[0] != namespace {
+			continue
+		}
+
+		if pod != "" && parts[1] != pod {
+			continue
+		}
+		if pod == "" && (app == "" || p.AppLabels[podKey] != app) {
+			continue
+		}
+
+		for _, name := range containers {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// AppLabelKey returns the label key the pods of app carry its name under, or ""
+// when skube init did not see the app. Any namespace matches when none is given.
+func (p *ClusterPatterns) AppLabelKey(namespace, app string) string {
+	if key, ok := p.AppLabelKeys[namespace+"/"+app]; ok || namespace != "" {
+		return key
+	}
+	keys := make([]string, 0, len(p.AppLabelKeys))
+	for appKey := range p.AppLabelKeys {
+		keys = append(keys, appKey)
+	}
+	// Sorted so that an app found under several keys always yields the same one
+	sort.Strings(keys)
+	for _, appKey := range keys {
+		if _, name, _ := strings.Cut(appKey, "/"); name == app {
+			return p.AppLabelKeys[appKey]
+		}
+	}
+	return ""
 }
 
-// IsClusterPatternsCacheStale checks if the patterns cache needs refresh
+// FullName returns the group-qualified plural that kubectl resolves unambiguously,
+// e.g. "rollouts.argoproj.io"
+func (r APIResource) FullName() string {
+	if r.Group == "" {
+		return r.Name
+	}
+	return r.Name + "." + r.Group
+}
+
+// Matches reports whether word names the resource type by its plural, kind,
+// short name or group-qualified plural, ignoring case
+func (r APIResource) Matches(word string) bool {
+	if strings.EqualFold(word, r.Name) || strings.EqualFold(word, r.Kind) || strings.EqualFold(word, r.FullName()) {
+		return true
+	}
+	for _, short := range r.ShortNames {
+		if strings.EqualFold(word, short) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAPIResource returns the learned resource type word names. Core types win
+// over types of other groups that share a name.
+func (p *ClusterPatterns) FindAPIResource(word string) (APIResource, bool) {
+	var found APIResource
+	ok := false
+	for _, r := range p.APIResources {
+		if !r.Matches(word) {
+			continue
+		}
+		if r.Group == "" {
+			return r, true
+		}
+		if !ok {
+			found, ok = r, true
+		}
+	}
+	return found, ok
+}
+
+// IsClusterPatternsCacheStale checks if the patterns cache needs refresh: it is
+// empty, or some kind of pattern outlived its TTL
 func IsClusterPatternsCacheStale() bool {
 	patterns, err := LoadClusterPatterns()
 	if err != nil {
 		// If we can't load, consider it stale
 		return true
 	}
+	return patterns.IsEmpty() || len(patterns.StaleKinds(time.Now())) > 0
+}
 
-	// Check if cache is empty (first run)
-	if len(patterns.Namespaces) == 0 && len(patterns.Deployments) == 0 {
-		return true
-	}
+// IsEmpty reports whether nothing was learned yet for the context (first run)
+func (p *ClusterPatterns) IsEmpty() bool {
+	return len(p.Namespaces) == 0 && len(p.Deployments) == 0
+}
 
-	// Check if cache has expired
-	return time.Since(patterns.LastUpdated) > patternsCacheTTL
+// StaleKinds returns the kinds of patterns whose TTL expired at now, in learning
+// order. Kinds never listed on their own, e.g. in caches from older versions, are stale.
+func (p *ClusterPatterns) StaleKinds(now time.Time) []string {
+	var stale []string
+	for _, kind := range PatternKinds {
+		refresh, ok := p.Refreshed[kind]
+		if !ok || now.Sub(refresh.At) > patternKindTTLs[kind] {
+			stale = append(stale, kind)
+		}
+	}
+	return stale
 }
 
 // GetClusterPatternsPath returns the full path to the patterns cache file for the current context
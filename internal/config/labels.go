@@ -0,0 +1,35 @@
+package config
+
+// DefaultAppLabelKeys are the pod label keys that name an app when none are configured
+var DefaultAppLabelKeys = []string{"app", "app.kubernetes.io/name", "k8s-app"}
+
+// Labels configures how skube recognizes apps on pods. Entries under Contexts
+// apply only while that kubectl context is active and replace the top-level keys.
+type Labels struct {
+	AppKeys  []string           `json:"app_keys,omitempty"` // label keys naming an app, most preferred first, e.g. "app.kubernetes.io/name"
+	Contexts map[string]*Labels `json:"contexts,omitempty"` // per kubectl context labels
+}
+
+// AppKeysFor returns the app label keys that apply in kubeContext
+func (l *Labels) AppKeysFor(kubeContext string) []string {
+	if l == nil {
+		return DefaultAppLabelKeys
+	}
+	if override, ok := l.Contexts[kubeContext]; ok && override != nil && len(override.AppKeys) > 0 {
+		return override.AppKeys
+	}
+	if len(l.AppKeys) > 0 {
+		return l.AppKeys
+	}
+	return DefaultAppLabelKeys
+}
+
+// LoadAppLabelKeys returns the app label keys configured for kubeContext, or the
+// defaults when the config sets none or cannot be read
+func LoadAppLabelKeys(kubeContext string) []string {
+	cfg, err := LoadAIConfig()
+	if err != nil {
+		return DefaultAppLabelKeys
+	}
+	return cfg.Labels.AppKeysFor(kubeContext)
+}
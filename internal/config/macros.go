@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Macro is a named workflow of natural-language steps, run as `skube <name> <params...>`.
+// Steps may reference {param} placeholders and {namespace}.
+type Macro struct {
+	Description string      `json:"description,omitempty"`
+	Params      []string    `json:"params,omitempty"`
+	Steps       []MacroStep `json:"steps"`
+}
+
+// MacroStep is one step of a macro. In the config it is either a plain string
+// or an object such as {"run": "logs of {app}", "on_error": "continue"}.
+type MacroStep struct {
+	Run     string `json:"run"`
+	OnError string `json:"on_error,omitempty"` // "stop" (default) or "continue"
+}
+
+// UnmarshalJSON accepts a step written as a plain string or as an object
+func (s *MacroStep) UnmarshalJSON(data []byte) error {
+	var run string
+	if err := json.Unmarshal(data, &run); err == nil {
+		s.Run = run
+		return nil
+	}
+
+	type step MacroStep
+	var full step
+	if err := json.Unmarshal(data, &full); err != nil {
+		return fmt.Errorf("macro step must be a string or {\"run\": ...}: %w", err)
+	}
+	if full.OnError != "" && full.OnError != "stop" && full.OnError != "continue" {
+		return fmt.Errorf("macro step on_error must be \"stop\" or \"continue\", got %q", full.OnError)
+	}
+	*s = MacroStep(full)
+	return nil
+}
+
+// LoadMacros returns the macros defined in the skube config
+func LoadMacros() (map[string]*Macro, error) {
+	cfg, err := LoadAIConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Macros, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const scaleHistoryFile = "scale-history.json"
+
+// scaleHistoryKey identifies a workload across kubectl contexts
+func scaleHistoryKey(kubeContext, namespace, workload string) string {
+	return kubeContext + "/" + namespace + "/" + workload
+}
+
+// loadScaleHistory reads the replica counts skube recorded before scaling, keyed by scaleHistoryKey
+func loadScaleHistory() (map[string]int, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	history := map[string]int{}
+	data, err := os.ReadFile(filepath.Join(configDir, scaleHistoryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// PreviousReplicas returns the replica count a workload had before skube last scaled it.
+// workload is a deployment name, or "kind/name" for other workloads.
+func PreviousReplicas(kubeContext, namespace, workload string) (int, bool) {
+	history, err := loadScaleHistory()
+	if err != nil {
+		return 0, false
+	}
+	replicas, ok := history[scaleHistoryKey(kubeContext, namespace, workload)]
+	return replicas, ok
+}
+
+// RecordPreviousReplicas remembers the replica count a workload had before scaling,
+// so that "scale <name> back" can restore it
+func RecordPreviousReplicas(kubeContext, namespace, workload string, replicas int) error {
+	history, err := loadScaleHistory()
+	if err != nil {
+		// A corrupt history file only loses "back"; start over
+		history = map[string]int{}
+	}
+	history[scaleHistoryKey(kubeContext, namespace, workload)] = replicas
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(configDir, scaleHistoryFile), data, 0644)
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	sessionsSubDir = "sessions"
+	sessionTTL     = 12 * time.Hour
+)
+
+// SessionTarget is the resource the last command of a terminal session acted on,
+// so that later commands can refer to it as "it" or "that app"
+type SessionTarget struct {
+	KubeContext    string    `json:"kubeContext"`
+	Namespace      string    `json:"namespace,omitempty"`
+	AppName        string    `json:"appName,omitempty"`
+	PodName        string    `json:"podName,omitempty"`
+	DeploymentName string    `json:"deploymentName,omitempty"`
+	WorkloadKind   string    `json:"workloadKind,omitempty"` // kind of DeploymentName when it is not a deployment
+	ServiceName    string    `json:"serviceName,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// SessionID identifies the terminal session skube runs in. SKUBE_SESSION wins,
+// then the terminal's own session ID, then the parent (shell) process ID.
+func SessionID() string {
+	for _, name := range []string{"SKUBE_SESSION", "TERM_SESSION_ID", "WT_SESSION"} {
+		if id := os.Getenv(name); id != "" {
+			return sanitizeContextName(id)
+		}
+	}
+	return "ppid-" + strconv.Itoa(os.Getppid())
+}
+
+// sessionPath returns the file holding the target of the current session
+func sessionPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, sessionsSubDir, SessionID()+".json"), nil
+}
+
+// LoadSessionTarget returns the target remembered for this terminal session.
+// Targets expire after a while and never carry over to another kubectl context;
+// nil is returned when there is none.
+func LoadSessionTarget() (*SessionTarget, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var target SessionTarget
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, err
+	}
+	if time.Since(target.UpdatedAt) > sessionTTL {
+		return nil, nil
+	}
+	if currentContext, err := GetCurrentKubeContext(); err == nil && currentContext != target.KubeContext {
+		return nil, nil
+	}
+	return &target, nil
+}
+
+// SaveSessionTarget remembers target for this terminal session and removes
+// the expired targets of other sessions
+func SaveSessionTarget(target *SessionTarget) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if target.KubeContext == "" {
+		target.KubeContext, _ = GetCurrentKubeContext()
+	}
+	target.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(target, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	pruneSessions(filepath.Dir(path))
+	return nil
+}
+
+// pruneSessions removes session files that are too old to be loaded again
+func pruneSessions(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) <= sessionTTL {
+			continue
+		}
+		os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
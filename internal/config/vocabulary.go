@@ -0,0 +1,69 @@
+package config
+
+// Vocabulary extends the natural-language parser with team-specific words.
+// Entries under Contexts apply only while that kubectl context is active and
+// take precedence over the top-level entries.
+type Vocabulary struct {
+	Verbs      map[string]string      `json:"verbs,omitempty"`      // word -> built-in command word, e.g. "kick": "restart"
+	Resources  map[string]string      `json:"resources,omitempty"`  // word -> built-in resource word, e.g. "dep": "deployment"
+	Namespaces map[string]string      `json:"namespaces,omitempty"` // nickname -> namespace, e.g. "live": "production-eu-1"
+	Names      map[string]string      `json:"names,omitempty"`      // nickname -> resource name, e.g. "gw": "api-gateway"
+	StopWords  []string               `json:"stop_words,omitempty"` // extra filler words to ignore
+	Contexts   map[string]*Vocabulary `json:"contexts,omitempty"`   // per kubectl context vocabulary
+}
+
+// LoadVocabulary returns the vocabulary from the skube config, merged with the
+// entries for the current kubectl context. A missing config yields an empty vocabulary.
+func LoadVocabulary() (*Vocabulary, error) {
+	cfg, err := LoadAIConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Vocabulary == nil {
+		return &Vocabulary{}, nil
+	}
+	if len(cfg.Vocabulary.Contexts) == 0 {
+		return cfg.Vocabulary, nil
+	}
+
+	kubeContext, err := GetCurrentKubeContext()
+	if err != nil {
+		// Without a context only the shared vocabulary applies
+		return cfg.Vocabulary.ForContext(""), nil
+	}
+	return cfg.Vocabulary.ForContext(kubeContext), nil
+}
+
+// ForContext returns the vocabulary that applies in kubeContext: the top-level
+// entries overridden by the entries configured for that context
+func (v *Vocabulary) ForContext(kubeContext string) *Vocabulary {
+	merged := &Vocabulary{
+		Verbs:      mergeWords(v.Verbs, nil),
+		Resources:  mergeWords(v.Resources, nil),
+		Namespaces: mergeWords(v.Namespaces, nil),
+		Names:      mergeWords(v.Names, nil),
+		StopWords:  append([]string(nil), v.StopWords...),
+	}
+
+	if override, ok := v.Contexts[kubeContext]; ok && override != nil {
+		merged.Verbs = mergeWords(merged.Verbs, override.Verbs)
+		merged.Resources = mergeWords(merged.Resources, override.Resources)
+		merged.Namespaces = mergeWords(merged.Namespaces, override.Namespaces)
+		merged.Names = mergeWords(merged.Names, override.Names)
+		merged.StopWords = append(merged.StopWords, override.StopWords...)
+	}
+
+	return merged
+}
+
+// mergeWords copies base and applies override on top of it
+func mergeWords(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for word, meaning := range base {
+		merged[word] = meaning
+	}
+	for word, meaning := range override {
+		merged[word] = meaning
+	}
+	return merged
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// learnedContainers returns the container names learned by `skube init` for a pod or app.
+// It is a variable to allow mocking in tests.
+var learnedContainers = func(namespace, app, pod string) []string {
+	patterns := learnedPatterns()
+	if patterns == nil {
+		return nil
+	}
+	return patterns.ContainersFor(namespace, app, pod)
+}
+
+// checkContainer makes sure the requested container exists in the target pod or app.
+// When it does not, the error lists the containers skube knows about.
+// When no container was requested and the target runs several, it prints them as a tip.
+func checkContainer(ctx *parser.Context, app, pod string) error {
+	known := learnedContainers(ctx.Namespace, app, pod)
+	if len(known) == 0 {
+		return nil
+	}
+
+	target := pod
+	if target == "" {
+		target = "app " + app
+	}
+
+	if ctx.Container == "" {
+		if len(known) > 1 && !ctx.AllContainers {
+			fmt.Printf("%s💡 %s has several containers: %s (pick one with: container <name>)%s\n",
+				config.ColorYellow, target, strings.Join(known, ", "), config.ColorReset)
+		}
+		return nil
+	}
+
+	for _, name := range known {
+		if name == ctx.Container {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("container %q not found in %s\nAvailable containers: %s", ctx.Container, target, strings.Join(known, ", "))
+}
+
+// appendContainer adds "-c <name>" to kubectl args when a container is set
+func appendContainer(args []string, ctx *parser.Context) []string {
+	if ctx.Container == "" {
+		return args
+	}
+	return append(args, "-c", ctx.Container)
+}
+
+// copyPodName extracts the pod from a "pod:/path" copy source or destination
+func copyPodName(ctx *parser.Context) string {
+	for _, path := range []string{ctx.SourcePath, ctx.DestPath} {
+		if idx := strings.Index(path, ":"); idx > 0 {
+			return path[:idx]
+		}
+	}
+	return ""
+}
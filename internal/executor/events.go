@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// eventList mirrors the parts of `kubectl get events -o json` that skube inspects
+type eventList struct {
+	Items []event `json:"items"`
+}
+
+type event struct {
+	Metadata struct {
+		Namespace         string    `json:"namespace"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Type           string    `json:"type"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	EventTime      time.Time `json:"eventTime"`
+	InvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"involvedObject"`
+}
+
+// lastSeen returns the most specific timestamp available for an event
+func (e event) lastSeen() time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime
+	}
+	return e.Metadata.CreationTimestamp
+}
+
+// sinceCutoff converts the context's time window into an absolute cutoff
+func sinceCutoff(ctx *parser.Context) (time.Time, error) {
+	if ctx.Since != "" {
+		d, err := time.ParseDuration(ctx.Since)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time window %q: %w", ctx.Since, err)
+		}
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, ctx.SinceTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q: %w", ctx.SinceTime, err)
+	}
+	return t, nil
+}
+
+// handleEventsSince lists events and prints only those seen after the time window start
+func handleEventsSince(ctx *parser.Context, extraArgs []string) error {
+	window := ctx.Since
+	if window == "" {
+		window = ctx.SinceTime
+	}
+	fmt.Printf("%s📅 Cluster Events since %s%s\n", config.ColorCyan, window, config.ColorReset)
+
+	cutoff, err := sinceCutoff(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"get", "events", "-o", "json"}, extraArgs...)
+	if ctx.DryRun {
+		fmt.Printf("%s📋 DRY RUN: Would execute:%s\n", config.ColorYellow, config.ColorReset)
+		fmt.Printf("kubectl %s\n", strings.Join(args, " "))
+		fmt.Printf("and show only events since %s\n", cutoff.Format(time.RFC3339))
+		return nil
+	}
+
+	output, err := execCommand("kubectl", args...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var list eventList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("failed to parse event list: %w", err)
+	}
+
+	var matches []event
+	for _, e := range list.Items {
+		if ctx.AppName != "" && !strings.Contains(e.InvolvedObject.Name, ctx.AppName) {
+			continue
+		}
+		if !e.lastSeen().Before(cutoff) {
+			matches = append(matches, e)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].lastSeen().Before(matches[j].lastSeen())
+	})
+
+	if len(matches) == 0 {
+		fmt.Printf("%sNo events since %s%s\n", config.ColorGreen, cutoff.Format(time.RFC3339), config.ColorReset)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	showNamespace := spansNamespaces(ctx)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, e := range matches {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", e.Metadata.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			formatAge(e.lastSeen()), e.Type, e.Reason,
+			strings.ToLower(e.InvolvedObject.Kind)+"/"+e.InvolvedObject.Name, e.Message)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+func TestHandleEventsSince(t *testing.T) {
+	recent := time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339)
+	old := time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)
+	fixture := fmt.Sprintf(`{"items":[
+ {"type":"Warning","reason":"BackOff","message":"recent failure","lastTimestamp":%q,"involvedObject":{"kind":"Pod","name":"api-1"}},
+ {"type":"Normal","reason":"Pulled","message":"old pull","lastTimestamp":%q,"involvedObject":{"kind":"Pod","name":"api-2"}}
+]}`, recent, old)
+
+	execCommand = fakeExecCommandWithOutput(fixture)
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "events", Namespace: "prod", Since: "1h"})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if !strings.Contains(output, "recent failure") || !strings.Contains(output, "pod/api-1") {
+		t.Errorf("Expected recent event in output, got %q", output)
+	}
+	if strings.Contains(output, "old pull") {
+		t.Errorf("Expected old event to be filtered out, got %q", output)
+	}
+}
@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+// eventTypes maps lowercased event types to the names kubectl filters on
+var eventTypes = map[string]string{
+	"normal": "Normal", "warning": "Warning", "warnings": "Warning",
+}
+
+// eventTypeTerms returns the field selector terms for ctx.EventType and excluded event types
+func eventTypeTerms(ctx *parser.Context) []string {
+	var terms []string
+	if ctx.EventType != "" {
+		terms = append(terms, "type="+ctx.EventType)
+	}
+	for _, term := range ctx.Exclusions {
+		if eventType := eventTypes[strings.ToLower(term)]; eventType != "" {
+			terms = append(terms, "type!="+eventType)
+		}
+	}
+	return terms
+}
+
+// exclusionGrepArgs builds an inverse grep that drops log lines containing any
+// excluded text, or nil when nothing is excluded
+func exclusionGrepArgs(ctx *parser.Context) []string {
+	if len(ctx.Exclusions) == 0 {
+		return nil
+	}
+	args := []string{"-v"}
+	if ctx.IgnoreCase {
+		args = append(args, "-i")
+	}
+	args = append(args, "-F")
+	for _, term := range ctx.Exclusions {
+		args = append(args, "-e", term)
+	}
+	return args
+}
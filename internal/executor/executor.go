@@ -1,11 +1,13 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/geminal/skube/internal/completion"
 	"github.com/geminal/skube/internal/config"
@@ -17,8 +19,21 @@ import (
 var execCommand = exec.Command
 
 func ExecuteCommand(ctx *parser.Context) error {
+	// Never act on a guess when a name matched several resources
+	if ctx.ParseError != nil {
+		return ctx.ParseError
+	}
+
+	// Show what "it" or "same namespace" was taken to mean
+	for _, note := range ctx.Notes {
+		fmt.Printf("%s💭 %s%s\n", config.ColorCyan, note, config.ColorReset)
+	}
+
 	// Sanitize inputs
-	ctx.SearchTerm = sanitizeInput(ctx.SearchTerm)
+	ctx.SearchTerm = sanitizePattern(ctx.SearchTerm)
+	ctx.Exclusions = sanitizeExclusions(ctx.Exclusions)
+	ctx.Selector = sanitizeInput(ctx.Selector)
+	ctx.Container = sanitizeInput(ctx.Container)
 	ctx.FilePath = sanitizeInput(ctx.FilePath)
 	ctx.SourcePath = sanitizeInput(ctx.SourcePath)
 	ctx.DestPath = sanitizeInput(ctx.DestPath)
@@ -41,6 +56,8 @@ func ExecuteCommand(ctx *parser.Context) error {
 		return handleScale(ctx)
 	case "rollback":
 		return handleRollback(ctx)
+	case parser.CmdRolloutStatus:
+		return handleRolloutStatus(ctx)
 	case "forward":
 		return handlePortForward(ctx)
 	case "describe":
@@ -49,6 +66,10 @@ func ExecuteCommand(ctx *parser.Context) error {
 		return handleServices(ctx)
 	case "deployments":
 		return handleDeployments(ctx)
+	case "statefulsets", "daemonsets", "jobs", "cronjobs":
+		return handleWorkloads(ctx)
+	case parser.CmdResources:
+		return handleResources(ctx)
 	case "namespaces":
 		return handleNamespaces(ctx)
 	case "nodes":
@@ -96,28 +117,95 @@ func ExecuteCommand(ctx *parser.Context) error {
 	}
 }
 
+// ExecuteSequence runs chained commands in order and stops at the first
+// step that fails.
+func ExecuteSequence(steps []*parser.Context) error {
+	if len(steps) == 1 {
+		return ExecuteCommand(steps[0])
+	}
+
+	var failed []error
+	for i, ctx := range steps {
+		fmt.Printf("%s▶ Step %d/%d: %s%s\n", config.ColorBlue, i+1, len(steps), ctx.Command, config.ColorReset)
+		if err := ExecuteCommand(ctx); err != nil {
+			err = fmt.Errorf("step %d (%s) failed: %w", i+1, ctx.Command, err)
+			if ctx.ContinueOnError {
+				fmt.Printf("%s⚠️  %v; continuing%s\n", config.ColorYellow, err, config.ColorReset)
+				failed = append(failed, err)
+				continue
+			}
+			if i+1 < len(steps) {
+				fmt.Printf("%s⏹  Skipping remaining %d step(s)%s\n", config.ColorYellow, len(steps)-i-1, config.ColorReset)
+			}
+			return errors.Join(append(failed, err)...)
+		}
+	}
+	return errors.Join(failed...)
+}
+
 func handleLogs(ctx *parser.Context) error {
+	if err := pickPod(ctx); err != nil {
+		return err
+	}
+
 	kubectlArgs := []string{"logs"}
 
-	if ctx.AppName != "" {
-		kubectlArgs = append(kubectlArgs, "-l", "app="+ctx.AppName)
+	if ctx.AppName != "" || (ctx.PodName == "" && ctx.Selector != "") {
+		kubectlArgs = append(kubectlArgs, "-l", labelSelector(ctx))
 		if ctx.Prefix {
 			kubectlArgs = append(kubectlArgs, "--prefix=true")
 		}
-		fmt.Printf("%s📋 Fetching logs from app: %s%s\n", config.ColorCyan, ctx.AppName, config.ColorReset)
+		if ctx.AppName != "" {
+			fmt.Printf("%s📋 Fetching logs from app: %s%s\n", config.ColorCyan, ctx.AppName, config.ColorReset)
+		} else {
+			fmt.Printf("%s📋 Fetching logs from pods matching: %s%s\n", config.ColorCyan, ctx.Selector, config.ColorReset)
+		}
 	} else if ctx.PodName != "" {
 		kubectlArgs = append(kubectlArgs, ctx.PodName)
 		fmt.Printf("%s📋 Fetching logs from pod: %s%s\n", config.ColorCyan, ctx.PodName, config.ColorReset)
+	} else if ctx.DeploymentName != "" {
+		// kubectl picks a pod of the workload
+		if err := checkWorkloadKind(ctx, "logs", parser.KwDeployment, parser.KwStatefulSet, parser.KwDaemonSet, parser.KwJob); err != nil {
+			return fmt.Errorf("%w\nRead the logs of one of its jobs instead", err)
+		}
+		kubectlArgs = append(kubectlArgs, workloadKind(ctx)+"/"+ctx.DeploymentName)
+		fmt.Printf("%s📋 Fetching logs from %s: %s%s\n", config.ColorCyan, workloadKind(ctx), ctx.DeploymentName, config.ColorReset)
 	} else {
 		return fmt.Errorf("need pod or app\nUsage: skube logs from app <name> in <namespace>\n       skube logs from pod <name> in <namespace>")
 	}
 
+	if err := checkContainer(ctx, ctx.AppName, ctx.PodName); err != nil {
+		return err
+	}
+	if ctx.AllContainers {
+		kubectlArgs = append(kubectlArgs, "--all-containers=true")
+	} else {
+		kubectlArgs = appendContainer(kubectlArgs, ctx)
+	}
+
+	previous := ctx.Previous
+	// Dry runs stay offline; following a crashed container's old instance makes no sense
+	if !previous && !ctx.Follow && !ctx.AllContainers && !ctx.DryRun {
+		if crash, ok := crashedLastInstance(ctx); ok {
+			fmt.Printf("%s💥 %s, showing logs from the previous instance%s\n", config.ColorYellow, crash, config.ColorReset)
+			previous = true
+		}
+	}
+	if previous {
+		kubectlArgs = append(kubectlArgs, "--previous")
+	}
+
 	if ctx.Follow {
 		kubectlArgs = append(kubectlArgs, "-f")
 	}
 	if ctx.TailLines > 0 {
 		kubectlArgs = append(kubectlArgs, "--tail="+strconv.Itoa(ctx.TailLines))
 	}
+	if ctx.Since != "" {
+		kubectlArgs = append(kubectlArgs, "--since="+ctx.Since)
+	} else if ctx.SinceTime != "" {
+		kubectlArgs = append(kubectlArgs, "--since-time="+ctx.SinceTime)
+	}
 	if ctx.MaxLogRequests > 0 {
 		kubectlArgs = append(kubectlArgs, "--max-log-requests="+strconv.Itoa(ctx.MaxLogRequests))
 	}
@@ -125,8 +213,16 @@ func handleLogs(ctx *parser.Context) error {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
 
+	// Exclusions run before the search so its color codes cannot hide excluded text
+	var grepStages [][]string
+	if exclude := exclusionGrepArgs(ctx); exclude != nil {
+		grepStages = append(grepStages, exclude)
+	}
 	if ctx.SearchTerm != "" {
-		return runKubectlPiped(kubectlArgs, []string{"--color=always", ctx.SearchTerm}, ctx.DryRun)
+		grepStages = append(grepStages, searchGrepArgs(ctx))
+	}
+	if len(grepStages) > 0 {
+		return runKubectlPiped(kubectlArgs, grepStages, ctx.DryRun)
 	}
 
 	err := runKubectl(kubectlArgs, ctx.DryRun)
@@ -135,23 +231,37 @@ func handleLogs(ctx *parser.Context) error {
 		if strings.Contains(err.Error(), "ContainerCreating") || strings.Contains(err.Error(), "CrashLoopBackOff") {
 			fmt.Printf("%s💡 Tip: The pod seems to be having trouble starting. Try describing it:%s\n", config.ColorYellow, config.ColorReset)
 			fmt.Printf("   skube describe pod %s\n", ctx.PodName)
+			if !previous {
+				fmt.Printf("   or read the logs from before the crash: skube previous logs from pod %s\n", ctx.PodName)
+			}
 		}
 	}
 	return err
 }
 
 func handleShell(ctx *parser.Context) error {
+	if err := pickPod(ctx); err != nil {
+		return err
+	}
 	if ctx.PodName == "" {
 		return fmt.Errorf("need pod name\nUsage: skube shell into pod <name> in <namespace>")
 	}
 
-	kubectlArgs := []string{"exec", "-it", ctx.PodName}
+	if err := checkContainer(ctx, "", ctx.PodName); err != nil {
+		return err
+	}
+
+	kubectlArgs := appendContainer([]string{"exec", "-it", ctx.PodName}, ctx)
 	if ctx.Namespace != "" {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
 	kubectlArgs = append(kubectlArgs, "--", "sh")
 
-	fmt.Printf("%s🐚 Opening shell in pod: %s%s\n", config.ColorCyan, ctx.PodName, config.ColorReset)
+	if ctx.Container != "" {
+		fmt.Printf("%s🐚 Opening shell in pod: %s (container %s)%s\n", config.ColorCyan, ctx.PodName, ctx.Container, config.ColorReset)
+	} else {
+		fmt.Printf("%s🐚 Opening shell in pod: %s%s\n", config.ColorCyan, ctx.PodName, config.ColorReset)
+	}
 	err := runKubectl(kubectlArgs, ctx.DryRun)
 	if err != nil && !ctx.DryRun {
 		if strings.Contains(err.Error(), "not found") {
@@ -163,6 +273,9 @@ func handleShell(ctx *parser.Context) error {
 }
 
 func handleRestart(ctx *parser.Context) error {
+	if err := pickPod(ctx); err != nil {
+		return err
+	}
 	if ctx.PodName != "" {
 		kubectlArgs := []string{"delete", "pod", ctx.PodName}
 		if ctx.Namespace != "" {
@@ -173,47 +286,83 @@ func handleRestart(ctx *parser.Context) error {
 	}
 
 	if ctx.DeploymentName == "" {
-		return fmt.Errorf("need deployment or pod name\nUsage: skube restart deployment <name> in <namespace>")
+		selector := labelSelector(ctx)
+		if selector == "" {
+			return fmt.Errorf("need deployment or pod name\nUsage: skube restart deployment <name> in <namespace>")
+		}
+
+		kubectlArgs := []string{"rollout", "restart", "deployment", "-l", selector}
+		if ctx.Namespace != "" {
+			kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
+		}
+		fmt.Printf("%s🔄 Restarting deployments matching: %s%s\n", config.ColorYellow, selector, config.ColorReset)
+		return runKubectl(kubectlArgs, ctx.DryRun)
 	}
 
-	kubectlArgs := []string{"rollout", "restart", "deployment", ctx.DeploymentName}
+	if err := checkWorkloadKind(ctx, "restart", parser.KwDeployment, parser.KwStatefulSet, parser.KwDaemonSet); err != nil {
+		return err
+	}
+
+	kubectlArgs := []string{"rollout", "restart", workloadKind(ctx), ctx.DeploymentName}
 	if ctx.Namespace != "" {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
 
-	fmt.Printf("%s🔄 Restarting deployment: %s%s\n", config.ColorYellow, ctx.DeploymentName, config.ColorReset)
+	fmt.Printf("%s🔄 Restarting %s: %s%s\n", config.ColorYellow, workloadKind(ctx), ctx.DeploymentName, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handlePods(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "pods", "-o", "wide"}
 
+	if selector := labelSelector(ctx); selector != "" {
+		kubectlArgs = append(kubectlArgs, "-l", selector)
+	}
+
+	if ctx.PodStatus != "" || ctx.MinRestarts > 0 {
+		// Status filters need the full pod JSON, evaluated client-side
+		filterArgs := appendNamespace(kubectlArgs[4:], ctx)
+		return handleFilteredPods(ctx, filterArgs)
+	}
+
 	if ctx.AppName != "" {
-		kubectlArgs = append(kubectlArgs, "-l", "app="+ctx.AppName)
 		fmt.Printf("%s📦 Listing pods from app: %s%s\n", config.ColorCyan, ctx.AppName, config.ColorReset)
+	} else if ctx.Selector != "" {
+		fmt.Printf("%s📦 Listing pods matching: %s%s\n", config.ColorCyan, ctx.Selector, config.ColorReset)
 	} else {
 		fmt.Printf("%s📦 Listing pods%s\n", config.ColorCyan, config.ColorReset)
 	}
 
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
 
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleScale(ctx *parser.Context) error {
-	if ctx.DeploymentName == "" || ctx.Replicas == "" {
-		return fmt.Errorf("need deployment and replicas\nUsage: skube scale deployment <name> to <N> in <namespace>")
+	if ctx.DeploymentName == "" || (ctx.Replicas == "" && ctx.ScaleOp == "") {
+		return fmt.Errorf("need deployment and replicas\nUsage: skube scale deployment <name> to <N> in <namespace>\n       skube scale <name> up by <N> | down by <N> | to double | to half | back")
+	}
+	if err := checkWorkloadKind(ctx, "scale", parser.KwDeployment, parser.KwStatefulSet); err != nil {
+		return err
+	}
+	if ctx.ScaleOp != "" {
+		return handleRelativeScale(ctx)
 	}
 
-	kubectlArgs := []string{"scale", "deployment", ctx.DeploymentName, "--replicas=" + ctx.Replicas}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
+	fmt.Printf("%s⚖️  Scaling %s %s to %s replicas%s\n", config.ColorYellow, workloadKind(ctx), ctx.DeploymentName, ctx.Replicas, config.ColorReset)
+	if ctx.DryRun {
+		return runKubectl(scaleArgs(ctx, ctx.Replicas), true)
 	}
 
-	fmt.Printf("%s⚖️  Scaling deployment %s to %s replicas%s\n", config.ColorYellow, ctx.DeploymentName, ctx.Replicas, config.ColorReset)
-	return runKubectl(kubectlArgs, ctx.DryRun)
+	// Remember the current count so that "scale <name> back" can restore it
+	current, currentErr := currentReplicas(ctx)
+	if err := runKubectl(scaleArgs(ctx, ctx.Replicas), false); err != nil {
+		return err
+	}
+	if currentErr == nil {
+		recordPreviousReplicas(ctx, current)
+	}
+	return nil
 }
 
 func handleRollback(ctx *parser.Context) error {
@@ -221,12 +370,16 @@ func handleRollback(ctx *parser.Context) error {
 		return fmt.Errorf("need deployment name\nUsage: skube rollback deployment <name> in <namespace>")
 	}
 
-	kubectlArgs := []string{"rollout", "undo", "deployment", ctx.DeploymentName}
+	if err := checkWorkloadKind(ctx, "rollback", parser.KwDeployment, parser.KwStatefulSet, parser.KwDaemonSet); err != nil {
+		return err
+	}
+
+	kubectlArgs := []string{"rollout", "undo", workloadKind(ctx), ctx.DeploymentName}
 	if ctx.Namespace != "" {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
 
-	fmt.Printf("%s⏪ Rolling back deployment: %s%s\n", config.ColorYellow, ctx.DeploymentName, config.ColorReset)
+	fmt.Printf("%s⏪ Rolling back %s: %s%s\n", config.ColorYellow, workloadKind(ctx), ctx.DeploymentName, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
@@ -271,9 +424,8 @@ func handleDescribe(ctx *parser.Context) error {
 func handleServices(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "services", "-o", "wide"}
 
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 
 	fmt.Printf("%s🌐 Listing services%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
@@ -282,39 +434,86 @@ func handleServices(ctx *parser.Context) error {
 func handleDeployments(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "deployments", "-o", "wide"}
 
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 
 	fmt.Printf("%s🚀 Listing deployments%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
+// handleResources lists a resource type learned by API discovery, such as a CRD
+func handleResources(ctx *parser.Context) error {
+	if ctx.ResourceType == "" {
+		return fmt.Errorf("need resource type\nUsage: skube get <resource> in <namespace>")
+	}
+
+	kubectlArgs := []string{"get", ctx.ResourceType, "-o", "wide"}
+
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
+
+	fmt.Printf("%s📚 Listing %s%s\n", config.ColorCyan, ctx.ResourceType, config.ColorReset)
+	return runKubectl(kubectlArgs, ctx.DryRun)
+}
+
 func handleStatus(ctx *parser.Context) error {
 	fmt.Printf("%s📊 Cluster Status%s\n\n", config.ColorGreen, config.ColorReset)
 
 	kubectlArgs := []string{"get", "all"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleEvents(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "events", "--sort-by=.lastTimestamp"}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx, eventTypeTerms(ctx)...)
+
+	if ctx.Since != "" || ctx.SinceTime != "" {
+		// kubectl has no time filter for events, so filter client-side
+		return handleEventsSince(ctx, kubectlArgs[3:])
+	}
+
+	fmt.Printf("%s📅 Cluster Events%s\n", config.ColorCyan, config.ColorReset)
+	if ctx.AppName != "" {
+		// Events name the pods and replica sets of an app, which start with its name
+		return runKubectlPiped(kubectlArgs, [][]string{{"-F", "-e", ctx.AppName}}, ctx.DryRun)
+	}
+	return runKubectl(kubectlArgs, ctx.DryRun)
+}
+
+func handleRolloutStatus(ctx *parser.Context) error {
+	name := ctx.DeploymentName
+	if name == "" {
+		name = ctx.AppName
+	}
+	if name == "" {
+		return fmt.Errorf("need deployment name\nUsage: skube rollout status of <name> in <namespace>")
+	}
+
+	if err := checkWorkloadKind(ctx, "rollout status", parser.KwDeployment, parser.KwStatefulSet, parser.KwDaemonSet); err != nil {
+		return err
+	}
+
+	kubectlArgs := []string{"rollout", "status", workloadKind(ctx) + "/" + name}
 	if ctx.Namespace != "" {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
 
-	fmt.Printf("%s📅 Cluster Events%s\n", config.ColorCyan, config.ColorReset)
+	fmt.Printf("%s🚦 Rollout status of %s: %s%s\n", config.ColorCyan, workloadKind(ctx), name, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleAll(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "all", "-o", "wide"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
+
+	if ctx.SearchTerm != "" {
+		// "find api in any namespace"
+		fmt.Printf("%s🔎 Finding resources matching: %s%s\n", config.ColorCyan, ctx.SearchTerm, config.ColorReset)
+		return runKubectlPiped(kubectlArgs, [][]string{searchGrepArgs(ctx)}, ctx.DryRun)
 	}
 
 	fmt.Printf("%s📋 All Resources%s\n", config.ColorCyan, config.ColorReset)
@@ -323,46 +522,51 @@ func handleAll(ctx *parser.Context) error {
 
 func handleNamespaces(ctx *parser.Context) error {
 	fmt.Printf("%s📂 Listing namespaces%s\n", config.ColorCyan, config.ColorReset)
-	return runKubectl([]string{"get", "namespaces"}, ctx.DryRun)
+	kubectlArgs := appendSelector([]string{"get", "namespaces"}, ctx.Selector)
+	if len(ctx.Exclusions) > 0 {
+		// "namespaces except kube-system"
+		var terms []string
+		for _, ns := range ctx.Exclusions {
+			terms = append(terms, "metadata.name!="+ns)
+		}
+		kubectlArgs = append(kubectlArgs, "--field-selector", strings.Join(terms, ","))
+	}
+	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleNodes(ctx *parser.Context) error {
 	fmt.Printf("%s🖥️  Listing nodes%s\n", config.ColorCyan, config.ColorReset)
-	return runKubectl([]string{"get", "nodes", "-o", "wide"}, ctx.DryRun)
+	return runKubectl(appendSelector([]string{"get", "nodes", "-o", "wide"}, ctx.Selector), ctx.DryRun)
 }
 
 func handleConfigMaps(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "configmaps"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 	fmt.Printf("%s📄 Listing configmaps%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleSecrets(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "secrets"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 	fmt.Printf("%s🔒 Listing secrets%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handleIngresses(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "ingress"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 	fmt.Printf("%s🌐 Listing ingresses%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
 
 func handlePVCs(ctx *parser.Context) error {
 	kubectlArgs := []string{"get", "pvc"}
-	if ctx.Namespace != "" {
-		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-	}
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
 	fmt.Printf("%s💾 Listing persistent volume claims%s\n", config.ColorCyan, config.ColorReset)
 	return runKubectl(kubectlArgs, ctx.DryRun)
 }
@@ -378,6 +582,15 @@ func handleApply(ctx *parser.Context) error {
 }
 
 func handleDelete(ctx *parser.Context) error {
+	if ctx.ResourceType != "" && ctx.ResourceName == "" && ctx.Selector != "" {
+		kubectlArgs := []string{"delete", ctx.ResourceType, "-l", ctx.Selector}
+		if ctx.Namespace != "" {
+			kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
+		}
+		fmt.Printf("%s🗑️  Deleting %s matching: %s%s\n", config.ColorRed, ctx.ResourceType, ctx.Selector, config.ColorReset)
+		return runKubectl(kubectlArgs, ctx.DryRun)
+	}
+
 	if ctx.ResourceType == "" || ctx.ResourceName == "" {
 		return fmt.Errorf("need resource type and name\nUsage: skube delete <resource> <name> in <namespace>")
 	}
@@ -442,9 +655,8 @@ func handleMetrics(ctx *parser.Context) error {
 	} else {
 		// Default to pods
 		kubectlArgs = append(kubectlArgs, "pods")
-		if ctx.Namespace != "" {
-			kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
-		}
+		kubectlArgs = appendNamespace(kubectlArgs, ctx)
+		kubectlArgs = appendSelector(kubectlArgs, labelSelector(ctx))
 		fmt.Printf("%s📊 Pod Metrics%s\n", config.ColorCyan, config.ColorReset)
 	}
 
@@ -456,7 +668,11 @@ func handleCopy(ctx *parser.Context) error {
 		return fmt.Errorf("need source and destination\nUsage: skube copy file <src> to <dest>")
 	}
 
-	kubectlArgs := []string{"cp", ctx.SourcePath, ctx.DestPath}
+	if err := checkContainer(ctx, "", copyPodName(ctx)); err != nil {
+		return err
+	}
+
+	kubectlArgs := appendContainer([]string{"cp", ctx.SourcePath, ctx.DestPath}, ctx)
 	if ctx.Namespace != "" {
 		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
 	}
@@ -497,6 +713,55 @@ func handleUpdate() error {
 	return nil
 }
 
+// labelSelector combines the app label and the extra selector from the context
+func labelSelector(ctx *parser.Context) string {
+	var terms []string
+	if ctx.AppName != "" {
+		terms = append(terms, appLabelKey(ctx.Namespace, ctx.AppName)+"="+ctx.AppName)
+	}
+	if ctx.Selector != "" {
+		terms = append(terms, ctx.Selector)
+	}
+	return strings.Join(terms, ",")
+}
+
+var (
+	learned     *config.ClusterPatterns
+	learnedOnce sync.Once
+)
+
+// learnedPatterns returns the cluster patterns of the current context, loading them
+// once per run. It returns nil when they cannot be loaded.
+func learnedPatterns() *config.ClusterPatterns {
+	learnedOnce.Do(func() {
+		if patterns, err := config.LoadClusterPatterns(); err == nil {
+			learned = patterns
+		}
+	})
+	return learned
+}
+
+// appLabelKey returns the label key naming app: the key `skube init` saw on its pods,
+// or else the first key configured for the context. It is a variable to allow mocking in tests.
+var appLabelKey = func(namespace, app string) string {
+	patterns := learnedPatterns()
+	if patterns == nil {
+		return config.LoadAppLabelKeys(kubeContextName())[0]
+	}
+	if key := patterns.AppLabelKey(namespace, app); key != "" {
+		return key
+	}
+	return config.LoadAppLabelKeys(patterns.KubeContext)[0]
+}
+
+// appendSelector adds "-l <selector>" to kubectl args when a selector is set
+func appendSelector(args []string, selector string) []string {
+	if selector == "" {
+		return args
+	}
+	return append(args, "-l", selector)
+}
+
 func runKubectl(args []string, dryRun bool) error {
 	if dryRun {
 		fmt.Printf("%s📋 DRY RUN: Would execute:%s\n", config.ColorYellow, config.ColorReset)
@@ -545,8 +810,12 @@ func runKubectl(args []string, dryRun bool) error {
 		return err
 	}
 
-	// Read and print output as it comes
+	// Read and print output as it comes. Both readers must finish before
+	// Wait, which closes the pipes.
+	var readers sync.WaitGroup
+	readers.Add(2)
 	go func() {
+		defer readers.Done()
 		buf := make([]byte, 1024)
 		for {
 			n, err := stdout.Read(buf)
@@ -560,6 +829,7 @@ func runKubectl(args []string, dryRun bool) error {
 	}()
 
 	go func() {
+		defer readers.Done()
 		buf := make([]byte, 1024)
 		for {
 			n, err := stderr.Read(buf)
@@ -572,6 +842,7 @@ func runKubectl(args []string, dryRun bool) error {
 		}
 	}()
 
+	readers.Wait()
 	err = cmd.Wait()
 
 	if err != nil {
@@ -581,38 +852,107 @@ func runKubectl(args []string, dryRun bool) error {
 	return nil
 }
 
-func runKubectlPiped(kubectlArgs []string, grepArgs []string, dryRun bool) error {
+// runKubectlPiped runs kubectl and pipes its output through one grep per stage
+func runKubectlPiped(kubectlArgs []string, grepStages [][]string, dryRun bool) error {
 	if dryRun {
 		fmt.Printf("%s📋 DRY RUN: Would execute:%s\n", config.ColorYellow, config.ColorReset)
-		fmt.Printf("kubectl %s | grep %s\n", strings.Join(kubectlArgs, " "), strings.Join(grepArgs, " "))
+		pipeline := "kubectl " + strings.Join(kubectlArgs, " ")
+		for _, grepArgs := range grepStages {
+			pipeline += " | grep " + strings.Join(quoteArgs(grepArgs), " ")
+		}
+		fmt.Println(pipeline)
 		return nil
 	}
 	kubectlCmd := execCommand("kubectl", kubectlArgs...)
-	grepCmd := execCommand("grep", grepArgs...)
+	kubectlCmd.Stderr = os.Stderr
 
-	pipe, err := kubectlCmd.StdoutPipe()
-	if err != nil {
-		return err
+	// Chain each grep to the output of the previous command
+	cmds := []*exec.Cmd{kubectlCmd}
+	for _, grepArgs := range grepStages {
+		grepCmd := execCommand("grep", grepArgs...)
+		pipe, err := cmds[len(cmds)-1].StdoutPipe()
+		if err != nil {
+			return err
+		}
+		grepCmd.Stdin = pipe
+		grepCmd.Stderr = os.Stderr
+		cmds = append(cmds, grepCmd)
 	}
+	cmds[len(cmds)-1].Stdout = os.Stdout
 
-	grepCmd.Stdin = pipe
-	grepCmd.Stdout = os.Stdout
-	grepCmd.Stderr = os.Stderr
-	kubectlCmd.Stderr = os.Stderr
+	// Start the readers first so no stage blocks on a full pipe
+	for i := len(cmds) - 1; i >= 0; i-- {
+		if err := cmds[i].Start(); err != nil {
+			return err
+		}
+	}
 
-	if err := grepCmd.Start(); err != nil {
-		return err
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			// grep exits with 1 when no line matched, which is no failure
+			var exitErr *exec.ExitError
+			if i > 0 && errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+				continue
+			}
+			return err
+		}
 	}
+	return nil
+}
 
-	if err := kubectlCmd.Start(); err != nil {
-		return err
+// searchGrepArgs builds the grep arguments for ctx.SearchTerm. Plain terms are
+// matched literally so that "[error]" or "a.b" mean what they say.
+func searchGrepArgs(ctx *parser.Context) []string {
+	args := []string{"--color=always"}
+	if ctx.IgnoreCase {
+		args = append(args, "-i")
+	}
+	if ctx.SearchRegex {
+		args = append(args, "-E")
+	} else {
+		args = append(args, "-F")
 	}
+	return append(args, "-e", ctx.SearchTerm)
+}
 
-	if err := kubectlCmd.Wait(); err != nil {
-		return err
+// quoteArgs quotes args containing spaces or shell characters for display
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t'\"\\$`|&;<>()[]{}*?!#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return quoted
+}
+
+// sanitizePattern checks a search pattern. Unlike sanitizeInput it keeps regex
+// characters such as | and $: the pattern goes to grep after -e, never through
+// a shell, so only line breaks and null bytes are refused.
+func sanitizePattern(input string) string {
+	if strings.ContainsAny(input, "\n\r\x00") {
+		return ""
 	}
 
-	return grepCmd.Wait()
+	// Limit length to prevent DoS
+	maxLength := 1024
+	if len(input) > maxLength {
+		input = input[:maxLength]
+	}
+
+	return input
+}
+
+// sanitizeExclusions checks excluded terms and drops the ones that were refused
+func sanitizeExclusions(terms []string) []string {
+	var kept []string
+	for _, term := range terms {
+		if term = sanitizePattern(term); term != "" {
+			kept = append(kept, term)
+		}
+	}
+	return kept
 }
 
 func sanitizeInput(input string) string {
@@ -6,7 +6,10 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/geminal/skube/internal/parser"
@@ -71,16 +74,62 @@ func fakeExecCommand(command string, args ...string) *exec.Cmd {
 	return cmd
 }
 
+// fakeExecCommandWithOutput mocks exec.Command and makes the helper print output verbatim
+func fakeExecCommandWithOutput(output string) func(string, ...string) *exec.Cmd {
+	return func(command string, args ...string) *exec.Cmd {
+		cmd := fakeExecCommand(command, args...)
+		cmd.Env = append(cmd.Env, "GO_HELPER_OUTPUT="+output)
+		return cmd
+	}
+}
+
 func TestHelperProcess(t *testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
 		return
 	}
+	if output := os.Getenv("GO_HELPER_OUTPUT"); output != "" {
+		fmt.Print(output)
+		os.Exit(0)
+	}
 	// Print the command and arguments to stdout so we can verify them
 	args := os.Args[3:]
 	fmt.Printf("MOCK_EXEC: %s\n", strings.Join(args, " "))
 	os.Exit(0)
 }
 
+func TestRunKubectlPrintsAllOutput(t *testing.T) {
+	// More than one read buffer, so a reader still busy when kubectl exits would drop output
+	output := strings.Repeat("NAME READY STATUS\n", 1000)
+	execCommand = fakeExecCommandWithOutput(output)
+	defer func() { execCommand = exec.Command }()
+
+	for i := 0; i < 20; i++ {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		var buf bytes.Buffer
+		done := make(chan struct{})
+		go func() {
+			io.Copy(&buf, r)
+			close(done)
+		}()
+
+		err := runKubectl([]string{"get", "pods"}, false)
+
+		w.Close()
+		os.Stdout = oldStdout
+		<-done
+
+		if err != nil {
+			t.Fatalf("runKubectl returned error: %v", err)
+		}
+		if buf.String() != output {
+			t.Fatalf("run %d: expected %d bytes of output, got %d", i+1, len(output), buf.Len())
+		}
+	}
+}
+
 func TestHandleLogs(t *testing.T) {
 	execCommand = fakeExecCommand
 	defer func() { execCommand = exec.Command }()
@@ -117,6 +166,52 @@ func TestHandleLogs(t *testing.T) {
 			},
 			expected: "MOCK_EXEC: kubectl logs mypod -f --tail=100",
 		},
+		{
+			name: "Logs for App with Extra Selector",
+			ctx: &parser.Context{
+				Command:   "logs",
+				AppName:   "myapp",
+				Selector:  "tier=backend",
+				Namespace: "qa",
+			},
+			expected: "MOCK_EXEC: kubectl logs -l app=myapp,tier=backend -n qa",
+		},
+		{
+			name: "Logs by Selector Only",
+			ctx: &parser.Context{
+				Command:  "logs",
+				Selector: "team=payments",
+			},
+			expected: "MOCK_EXEC: kubectl logs -l team=payments",
+		},
+		{
+			name: "Logs Since Duration",
+			ctx: &parser.Context{
+				Command: "logs",
+				PodName: "mypod",
+				Since:   "15m",
+			},
+			expected: "MOCK_EXEC: kubectl logs mypod --since=15m",
+		},
+		{
+			name: "Logs Since Time",
+			ctx: &parser.Context{
+				Command:   "logs",
+				PodName:   "mypod",
+				SinceTime: "2024-05-01T10:30:00Z",
+			},
+			expected: "MOCK_EXEC: kubectl logs mypod --since-time=2024-05-01T10:30:00Z",
+		},
+		{
+			name: "Previous Logs",
+			ctx: &parser.Context{
+				Command:   "logs",
+				AppName:   "api",
+				Namespace: "prod",
+				Previous:  true,
+			},
+			expected: "MOCK_EXEC: kubectl logs -l app=api --previous -n prod",
+		},
 	}
 
 	for _, tt := range tests {
@@ -168,6 +263,15 @@ func TestHandlePods(t *testing.T) {
 			},
 			expected: "MOCK_EXEC: kubectl get pods -o wide -n dev",
 		},
+		{
+			name: "Get Pods by Selector",
+			ctx: &parser.Context{
+				Command:   "pods",
+				Selector:  "tier=backend",
+				Namespace: "prod",
+			},
+			expected: "MOCK_EXEC: kubectl get pods -o wide -l tier=backend -n prod",
+		},
 	}
 
 	for _, tt := range tests {
@@ -275,6 +379,16 @@ func TestHandleDelete(t *testing.T) {
 			},
 			expected: "MOCK_EXEC: kubectl delete deployment web",
 		},
+		{
+			name: "Delete Pods by Selector",
+			ctx: &parser.Context{
+				Command:      "delete",
+				ResourceType: "pod",
+				Selector:     "job=cleanup",
+				Namespace:    "dev",
+			},
+			expected: "MOCK_EXEC: kubectl delete pod -l job=cleanup -n dev",
+		},
 	}
 
 	for _, tt := range tests {
@@ -300,3 +414,722 @@ func TestHandleDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteSequence(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	t.Run("Runs steps in order", func(t *testing.T) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := ExecuteSequence([]*parser.Context{
+			{Command: "restart", DeploymentName: "api", Namespace: "prod"},
+			{Command: "pods", Namespace: "prod"},
+		})
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		output := buf.String()
+
+		if err != nil {
+			t.Errorf("ExecuteSequence returned error: %v", err)
+		}
+		first := strings.Index(output, "MOCK_EXEC: kubectl rollout restart deployment api -n prod")
+		second := strings.Index(output, "MOCK_EXEC: kubectl get pods -o wide -n prod")
+		if first == -1 || second == -1 || first > second {
+			t.Errorf("Expected restart then get pods, got %q", output)
+		}
+	})
+
+	t.Run("Stops at first failure", func(t *testing.T) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := ExecuteSequence([]*parser.Context{
+			{Command: "logs"},
+			{Command: "pods"},
+		})
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		output := buf.String()
+
+		if err == nil || !strings.Contains(err.Error(), "step 1 (logs) failed") {
+			t.Errorf("Expected step 1 failure, got %v", err)
+		}
+		if strings.Contains(output, "MOCK_EXEC") {
+			t.Errorf("Expected no further steps to run, got %q", output)
+		}
+	})
+
+	t.Run("Continues past failures when allowed", func(t *testing.T) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := ExecuteSequence([]*parser.Context{
+			{Command: "logs", ContinueOnError: true},
+			{Command: "pods", Namespace: "prod"},
+		})
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		output := buf.String()
+
+		if err == nil || !strings.Contains(err.Error(), "step 1 (logs) failed") {
+			t.Errorf("Expected step 1 failure to be reported, got %v", err)
+		}
+		if !strings.Contains(output, "MOCK_EXEC: kubectl get pods -o wide -n prod") {
+			t.Errorf("Expected step 2 to run, got %q", output)
+		}
+	})
+}
+
+func TestRolloutStatusAndWarningEvents(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+	}{
+		{
+			name:     "Rollout Status",
+			ctx:      &parser.Context{Command: "rollout-status", AppName: "billing", Namespace: "prod"},
+			expected: "MOCK_EXEC: kubectl rollout status deployment/billing -n prod",
+		},
+		{
+			name:     "Warning Events",
+			ctx:      &parser.Context{Command: "events", EventType: "Warning", Namespace: "prod"},
+			expected: "MOCK_EXEC: kubectl get events --sort-by=.lastTimestamp -n prod --field-selector type=Warning",
+		},
+		{
+			name:     "Warning Events of App",
+			ctx:      &parser.Context{Command: "events", EventType: "Warning", AppName: "billing", DryRun: true},
+			expected: "kubectl get events --sort-by=.lastTimestamp --field-selector type=Warning | grep -F -e billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+	}{
+		{
+			name:     "Plain Phrase",
+			ctx:      &parser.Context{Command: "logs", AppName: "api", SearchTerm: "connection refused", DryRun: true},
+			expected: "kubectl logs -l app=api | grep --color=always -F -e 'connection refused'",
+		},
+		{
+			name:     "Regex Ignoring Case",
+			ctx:      &parser.Context{Command: "logs", PodName: "api-1", SearchTerm: "time(d )?out|5..", SearchRegex: true, IgnoreCase: true, DryRun: true},
+			expected: "kubectl logs api-1 | grep --color=always -i -E -e 'time(d )?out|5..'",
+		},
+		{
+			name:     "Leading Dash Stays a Pattern",
+			ctx:      &parser.Context{Command: "logs", PodName: "api-1", SearchTerm: "-v", DryRun: true},
+			expected: "kubectl logs api-1 | grep --color=always -F -e -v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestExclusions(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+	}{
+		{
+			name:     "Pods Except Namespaces",
+			ctx:      &parser.Context{Command: "pods", Exclusions: []string{"kube-system", "monitoring"}, DryRun: true},
+			expected: "kubectl get pods -o wide --all-namespaces --field-selector metadata.namespace!=kube-system,metadata.namespace!=monitoring",
+		},
+		{
+			name:     "Namespace Wins Over Exclusions",
+			ctx:      &parser.Context{Command: "deployments", Namespace: "prod", Exclusions: []string{"kube-system"}, DryRun: true},
+			expected: "kubectl get deployments -o wide -n prod\n",
+		},
+		{
+			name:     "Dropped Exclusions Are Reported",
+			ctx:      &parser.Context{Command: "pods", Namespace: "prod", Exclusions: []string{"kube-system"}, DryRun: true},
+			expected: "Not excluding kube-system: only namespace prod is listed",
+		},
+		{
+			name:     "Logs Without Text",
+			ctx:      &parser.Context{Command: "logs", AppName: "api", Exclusions: []string{"healthcheck", "GET /ready"}, DryRun: true},
+			expected: "kubectl logs -l app=api | grep -v -F -e healthcheck -e 'GET /ready'\n",
+		},
+		{
+			name:     "Logs Without Text and Search",
+			ctx:      &parser.Context{Command: "logs", AppName: "api", Exclusions: []string{"healthcheck"}, SearchTerm: "error", IgnoreCase: true, DryRun: true},
+			expected: "kubectl logs -l app=api | grep -v -i -F -e healthcheck | grep --color=always -i -F -e error",
+		},
+		{
+			name:     "Events Excluding Type",
+			ctx:      &parser.Context{Command: "events", Namespace: "prod", Exclusions: []string{"normal"}, DryRun: true},
+			expected: "kubectl get events --sort-by=.lastTimestamp -n prod --field-selector type!=Normal",
+		},
+		{
+			name:     "Events Excluding Namespace and Type",
+			ctx:      &parser.Context{Command: "events", Exclusions: []string{"kube-system", "Normal"}, DryRun: true},
+			expected: "kubectl get events --sort-by=.lastTimestamp --all-namespaces --field-selector type!=Normal,metadata.namespace!=kube-system",
+		},
+		{
+			name:     "Namespaces Except",
+			ctx:      &parser.Context{Command: "namespaces", Exclusions: []string{"kube-system"}, DryRun: true},
+			expected: "kubectl get namespaces --field-selector metadata.name!=kube-system",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestAllNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+	}{
+		{
+			name:     "Pods Everywhere",
+			ctx:      &parser.Context{Command: "pods", AllNamespaces: true, DryRun: true},
+			expected: "kubectl get pods -o wide --all-namespaces\n",
+		},
+		{
+			name:     "Metrics Everywhere",
+			ctx:      &parser.Context{Command: "metrics", AllNamespaces: true, DryRun: true},
+			expected: "kubectl top pods --all-namespaces\n",
+		},
+		{
+			name:     "Warning Events Everywhere",
+			ctx:      &parser.Context{Command: "events", EventType: "Warning", AllNamespaces: true, DryRun: true},
+			expected: "kubectl get events --sort-by=.lastTimestamp --all-namespaces --field-selector type=Warning",
+		},
+		{
+			name:     "Find by Name in Any Namespace",
+			ctx:      &parser.Context{Command: "all", SearchTerm: "api", AllNamespaces: true, DryRun: true},
+			expected: "kubectl get all -o wide --all-namespaces | grep --color=always -F -e api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestRunKubectlPipedStages(t *testing.T) {
+	if _, err := exec.LookPath("grep"); err != nil {
+		t.Skip("grep not available")
+	}
+	// Fake kubectl output, real grep stages
+	fakeKubectl := fakeExecCommandWithOutput("GET /healthz 200\nGET /orders 500\nPOST /orders 201\n")
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		if command == "kubectl" {
+			return fakeKubectl(command, args...)
+		}
+		return exec.Command(command, args...)
+	}
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runKubectlPiped([]string{"logs", "api"}, [][]string{{"-v", "-F", "-e", "healthz"}, {"-F", "-e", "orders"}}, false)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("runKubectlPiped returned error: %v", err)
+	}
+	expected := "GET /orders 500\nPOST /orders 201\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLogsAllLinesExcluded(t *testing.T) {
+	if _, err := exec.LookPath("grep"); err != nil {
+		t.Skip("grep not available")
+	}
+	// Excluding every line prints nothing rather than failing
+	fakeKubectl := fakeExecCommandWithOutput("GET /healthz 200\nGET /healthz 200\n")
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		if command == "kubectl" {
+			return fakeKubectl(command, args...)
+		}
+		return exec.Command(command, args...)
+	}
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "logs", PodName: "api-1", Exclusions: []string{"healthz"}, Follow: true})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "healthz") {
+		t.Errorf("expected excluded lines to be dropped, got %q", buf.String())
+	}
+}
+
+func TestRunKubectlPipedNoMatches(t *testing.T) {
+	if _, err := exec.LookPath("grep"); err != nil {
+		t.Skip("grep not available")
+	}
+	// grep exits with 1 when nothing matches: an app without events is no failure
+	fakeKubectl := fakeExecCommandWithOutput("LAST SEEN   TYPE     REASON   OBJECT\n")
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		if command == "kubectl" {
+			return fakeKubectl(command, args...)
+		}
+		return exec.Command(command, args...)
+	}
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runKubectlPiped([]string{"get", "events"}, [][]string{{"-F", "-e", "-billing.*"}}, false)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("runKubectlPiped returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestSanitizePattern(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"error|warn", "error|warn"},
+		{"^5\\d\\d$", "^5\\d\\d$"},
+		{"line\nbreak", ""},
+		{"null\x00byte", ""},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizePattern(tt.input); got != tt.expected {
+			t.Errorf("sanitizePattern(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSelectorHandlers(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+	}{
+		{
+			name: "Restart Deployments by Selector",
+			ctx: &parser.Context{
+				Command:  "restart",
+				Selector: "tier=backend",
+			},
+			expected: "MOCK_EXEC: kubectl rollout restart deployment -l tier=backend",
+		},
+		{
+			name: "Restart App",
+			ctx: &parser.Context{
+				Command:   "restart",
+				AppName:   "api",
+				Namespace: "prod",
+			},
+			expected: "MOCK_EXEC: kubectl rollout restart deployment -l app=api -n prod",
+		},
+		{
+			name: "List Services by Selector",
+			ctx: &parser.Context{
+				Command:   "services",
+				Selector:  "team=payments",
+				Namespace: "qa",
+			},
+			expected: "MOCK_EXEC: kubectl get services -o wide -n qa -l team=payments",
+		},
+		{
+			name: "List Deployments by Selector",
+			ctx: &parser.Context{
+				Command:  "deployments",
+				Selector: "team=payments",
+			},
+			expected: "MOCK_EXEC: kubectl get deployments -o wide -l team=payments",
+		},
+		{
+			name: "List Learned Resource Type by Selector",
+			ctx: &parser.Context{
+				Command:      parser.CmdResources,
+				ResourceType: "rollouts.argoproj.io",
+				Selector:     "team=payments",
+				Namespace:    "prod",
+			},
+			expected: "MOCK_EXEC: kubectl get rollouts.argoproj.io -o wide -n prod -l team=payments",
+		},
+		{
+			name: "Describe Learned Resource Type",
+			ctx: &parser.Context{
+				Command:      "describe",
+				ResourceType: "virtualservices.networking.istio.io",
+				ResourceName: "reviews",
+			},
+			expected: "MOCK_EXEC: kubectl describe virtualservices.networking.istio.io reviews",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestContainerHandlers(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	oldLearned := learnedContainers
+	learnedContainers = func(namespace, app, pod string) []string {
+		return []string{"app", "istio-proxy"}
+	}
+	defer func() { learnedContainers = oldLearned }()
+
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+		wantErr  string
+	}{
+		{
+			name: "Logs from Container",
+			ctx: &parser.Context{
+				Command:   "logs",
+				AppName:   "api",
+				Container: "istio-proxy",
+			},
+			expected: "MOCK_EXEC: kubectl logs -l app=api -c istio-proxy",
+		},
+		{
+			name: "Logs from All Containers",
+			ctx: &parser.Context{
+				Command:       "logs",
+				PodName:       "api-0",
+				AllContainers: true,
+			},
+			expected: "MOCK_EXEC: kubectl logs api-0 --all-containers=true",
+		},
+		{
+			name: "Shell into Container",
+			ctx: &parser.Context{
+				Command:   "shell",
+				PodName:   "api-0",
+				Container: "app",
+				Namespace: "prod",
+			},
+			expected: "MOCK_EXEC: kubectl exec -it api-0 -c app -n prod -- sh",
+		},
+		{
+			name: "Shell without Container Lists Choices",
+			ctx: &parser.Context{
+				Command: "shell",
+				PodName: "api-0",
+			},
+			expected: "several containers: app, istio-proxy",
+		},
+		{
+			name: "Copy from Container",
+			ctx: &parser.Context{
+				Command:    "copy",
+				SourcePath: "api-0:/tmp/dump",
+				DestPath:   "dump",
+				Container:  "app",
+			},
+			expected: "MOCK_EXEC: kubectl cp api-0:/tmp/dump dump -c app",
+		},
+		{
+			name: "Unknown Container",
+			ctx: &parser.Context{
+				Command:   "logs",
+				PodName:   "api-0",
+				Container: "worker",
+			},
+			wantErr: "Available containers: app, istio-proxy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestExecuteCommandParseError(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	ctx := &parser.Context{
+		Command:    "restart",
+		AppName:    "api",
+		ParseError: fmt.Errorf("\"api\" matches several deployments"),
+	}
+
+	if err := ExecuteCommand(ctx); err == nil || !strings.Contains(err.Error(), "matches several deployments") {
+		t.Errorf("Expected the parse error to stop execution, got %v", err)
+	}
+}
+
+func TestExecuteCommandNotes(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{
+		Command:   "restart",
+		AppName:   "billing",
+		Namespace: "prod",
+		Notes:     []string{`"it" → app billing`, "namespace prod from the last command"},
+	})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	for _, expected := range []string{`"it" → app billing`, "namespace prod from the last command", "MOCK_EXEC: kubectl rollout restart deployment -l app=billing -n prod"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output containing %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestLabelSelectorUsesAppLabelKey(t *testing.T) {
+	oldKey := appLabelKey
+	appLabelKey = func(namespace, app string) string {
+		if namespace == "kube-system" && app == "coredns" {
+			return "k8s-app"
+		}
+		return "app.kubernetes.io/name"
+	}
+	defer func() { appLabelKey = oldKey }()
+
+	tests := []struct {
+		ctx      *parser.Context
+		expected string
+	}{
+		{&parser.Context{AppName: "coredns", Namespace: "kube-system"}, "k8s-app=coredns"},
+		{&parser.Context{AppName: "api", Selector: "tier=web"}, "app.kubernetes.io/name=api,tier=web"},
+		{&parser.Context{Selector: "tier=web"}, "tier=web"},
+	}
+
+	for _, tt := range tests {
+		if got := labelSelector(tt.ctx); got != tt.expected {
+			t.Errorf("labelSelector(%+v) = %q, want %q", tt.ctx, got, tt.expected)
+		}
+	}
+}
+
+func TestAppLabelKeyWithoutLearnedPatterns(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake kubectl is a shell script")
+	}
+	// A kubectl whose current context is staging, whose learned patterns are corrupt
+	bin := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1 $2\" = \"config current-context\" ] && echo staging && exit 0\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "kubectl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "skube")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"labels": {"app_keys": ["app"], "contexts": {"staging": {"app_keys": ["app.kubernetes.io/instance"]}}}}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patternsDir := filepath.Join(configDir, "patterns")
+	if err := os.MkdirAll(patternsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(patternsDir, "staging.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	learnedOnce, learned = sync.Once{}, nil
+	t.Cleanup(func() { learnedOnce, learned = sync.Once{}, nil })
+
+	if got := appLabelKey("prod", "billing"); got != "app.kubernetes.io/instance" {
+		t.Errorf("appLabelKey() = %q, want the key configured for the current context", got)
+	}
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// localCommands are handled by skube itself and never map to a kubectl command
+var localCommands = map[string]bool{
+	"completion": true,
+	"update":     true,
+	"version":    true,
+	"help":       true,
+}
+
+// ExplainParse prints how args were understood: the parsing stage that consumed
+// each token, how each name was resolved against the learned cluster patterns,
+// and finally the kubectl command skube would run. Nothing is executed.
+func ExplainParse(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("need a command to explain\nUsage: skube explain-parse <command>")
+	}
+
+	ctx, trace := parser.ExplainParse(args)
+
+	fmt.Printf("%s🔍 Tokens%s\n", config.ColorCyan, config.ColorReset)
+	for _, tok := range trace.Tokens {
+		line := fmt.Sprintf("  %-24s %-18s", strings.Join(tok.Tokens, " "), tok.Stage)
+		if len(tok.Changes) > 0 {
+			line += " " + strings.Join(tok.Changes, ", ")
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Printf("\n%s🎯 Name resolution%s\n", config.ColorCyan, config.ColorReset)
+	if !trace.PatternsLoaded {
+		fmt.Println("  No learned cluster patterns; names are used as typed (run 'skube init')")
+	} else if len(trace.Resolutions) == 0 {
+		fmt.Println("  No names to resolve")
+	}
+	for _, res := range trace.Resolutions {
+		fmt.Printf("  %s\n", res)
+	}
+
+	fmt.Printf("\n%s⚙️  Result%s\n", config.ColorCyan, config.ColorReset)
+	if ctx.Command == "" {
+		return fmt.Errorf("no command recognized")
+	}
+	if localCommands[ctx.Command] {
+		fmt.Printf("  %q is handled by skube itself and runs no kubectl command\n", ctx.Command)
+		return nil
+	}
+
+	ctx.DryRun = true
+	return ExecuteCommand(ctx)
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExplainParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name: "stages and kubectl command",
+			args: []string{"logs", "of", "api", "in", "prod", "follow"},
+			expected: []string{
+				"parseCommand",
+				"parsePrepositions",
+				"AppName=api",
+				"parseFlags",
+				"kubectl logs -l app=api -f -n prod",
+			},
+		},
+		{
+			name:     "local command",
+			args:     []string{"version"},
+			expected: []string{"runs no kubectl command"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExplainParse(tt.args)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if err != nil {
+				t.Fatalf("ExplainParse returned error: %v", err)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output containing %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// appendNamespace scopes kubectl args to ctx.Namespace, or to all namespaces
+// for "everywhere" and for listings that exclude namespaces. Excluded
+// namespaces and fieldTerms are merged into one --field-selector. A listing
+// of one namespace has no other namespaces to exclude, which the user is told.
+func appendNamespace(args []string, ctx *parser.Context, fieldTerms ...string) []string {
+	if ctx.Namespace != "" {
+		args = append(args, "-n", ctx.Namespace)
+		if excluded := excludedNamespaces(ctx); len(excluded) > 0 {
+			fmt.Printf("%s⚠️  Not excluding %s: only namespace %s is listed%s\n",
+				config.ColorYellow, strings.Join(excluded, ", "), ctx.Namespace, config.ColorReset)
+		}
+	} else if spansNamespaces(ctx) {
+		args = append(args, "--all-namespaces")
+		for _, ns := range excludedNamespaces(ctx) {
+			fieldTerms = append(fieldTerms, "metadata.namespace!="+ns)
+		}
+	}
+	if len(fieldTerms) > 0 {
+		args = append(args, "--field-selector", strings.Join(fieldTerms, ","))
+	}
+	return args
+}
+
+// excludedNamespaces returns the exclusions that name namespaces. For events,
+// exclusions that are event types ("excluding normal") are not namespaces.
+func excludedNamespaces(ctx *parser.Context) []string {
+	var namespaces []string
+	for _, term := range ctx.Exclusions {
+		if ctx.Command == "events" && eventTypes[strings.ToLower(term)] != "" {
+			continue
+		}
+		namespaces = append(namespaces, term)
+	}
+	return namespaces
+}
+
+// spansNamespaces reports whether a listing covers all namespaces, in which
+// case output needs a namespace column
+func spansNamespaces(ctx *parser.Context) bool {
+	return ctx.Namespace == "" && (ctx.AllNamespaces || len(excludedNamespaces(ctx)) > 0)
+}
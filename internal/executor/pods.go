@@ -0,0 +1,434 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// podList mirrors the parts of `kubectl get pods -o json` that skube inspects
+type podList struct {
+	Items []pod `json:"items"`
+}
+
+type pod struct {
+	Metadata struct {
+		Name              string            `json:"name"`
+		Namespace         string            `json:"namespace"`
+		CreationTimestamp time.Time         `json:"creationTimestamp"`
+		Labels            map[string]string `json:"labels"`
+		Annotations       map[string]string `json:"annotations"`
+		OwnerReferences   []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		NodeName   string `json:"nodeName"`
+		Containers []struct {
+			Name string `json:"name"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string `json:"phase"`
+		Reason     string `json:"reason"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		ContainerStatuses []containerStatus `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+type containerStatus struct {
+	Name         string         `json:"name"`
+	Ready        bool           `json:"ready"`
+	RestartCount int            `json:"restartCount"`
+	State        containerState `json:"state"`
+	LastState    containerState `json:"lastState"`
+}
+
+type containerState struct {
+	Waiting *struct {
+		Reason string `json:"reason"`
+	} `json:"waiting"`
+	Running *struct {
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"running"`
+	Terminated *struct {
+		Reason   string `json:"reason"`
+		ExitCode int    `json:"exitCode"`
+	} `json:"terminated"`
+}
+
+// crashReasons are container waiting reasons that mean the container keeps failing
+var crashReasons = map[string]bool{
+	"CrashLoopBackOff":     true,
+	"Error":                true,
+	"RunContainerError":    true,
+	"CreateContainerError": true,
+}
+
+// getPodList runs `kubectl get pods -o json` with the given extra args and decodes the result
+func getPodList(extraArgs []string) (*podList, error) {
+	args := append([]string{"get", "pods", "-o", "json"}, extraArgs...)
+	output, err := execCommand("kubectl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var list podList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %w", err)
+	}
+	return &list, nil
+}
+
+// matchesPodStatus reports whether a pod matches the status filter and restart threshold
+func matchesPodStatus(p pod, status string, minRestarts int) bool {
+	if minRestarts > 0 && p.restarts() < minRestarts {
+		return false
+	}
+
+	switch status {
+	case "":
+		return true
+	case "crashing":
+		for _, cs := range p.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && crashReasons[cs.State.Waiting.Reason] {
+				return true
+			}
+			if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+				return true
+			}
+		}
+		return false
+	case "pending":
+		return p.Status.Phase == "Pending"
+	case "failed":
+		return p.Status.Phase == "Failed"
+	case "evicted":
+		return p.Status.Phase == "Failed" && p.Status.Reason == "Evicted"
+	case "not-ready":
+		if p.Status.Phase == "Succeeded" {
+			return false
+		}
+		for _, cond := range p.Status.Conditions {
+			if cond.Type == "Ready" {
+				return cond.Status != "True"
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// restarts returns the total restart count across the pod's containers
+func (p pod) restarts() int {
+	total := 0
+	for _, cs := range p.Status.ContainerStatuses {
+		total += cs.RestartCount
+	}
+	return total
+}
+
+// displayStatus mimics the STATUS column of `kubectl get pods`
+func (p pod) displayStatus() string {
+	if p.Status.Reason != "" {
+		return p.Status.Reason
+	}
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+	return p.Status.Phase
+}
+
+// readyCount returns the READY column value, e.g. "1/2"
+func (p pod) readyCount() string {
+	ready := 0
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers))
+}
+
+// handleFilteredPods lists pods and prints only those matching the status filters
+func handleFilteredPods(ctx *parser.Context, extraArgs []string) error {
+	filter := describePodFilter(ctx)
+	fmt.Printf("%s📦 Listing %s pods%s\n", config.ColorCyan, filter, config.ColorReset)
+
+	if ctx.DryRun {
+		fmt.Printf("%s📋 DRY RUN: Would execute:%s\n", config.ColorYellow, config.ColorReset)
+		fmt.Printf("kubectl %s\n", strings.Join(append([]string{"get", "pods", "-o", "json"}, extraArgs...), " "))
+		fmt.Printf("and show only %s pods\n", filter)
+		return nil
+	}
+
+	list, err := getPodList(extraArgs)
+	if err != nil {
+		return err
+	}
+
+	var matches []pod
+	for _, p := range list.Items {
+		if matchesPodStatus(p, ctx.PodStatus, ctx.MinRestarts) {
+			matches = append(matches, p)
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("%sNo %s pods found%s\n", config.ColorGreen, filter, config.ColorReset)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	showNamespace := spansNamespaces(ctx)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODE")
+	for _, p := range matches {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", p.Metadata.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			p.Metadata.Name, p.readyCount(), p.displayStatus(), p.restarts(),
+			formatAge(p.Metadata.CreationTimestamp), p.Spec.NodeName)
+	}
+	return w.Flush()
+}
+
+// describePodFilter returns a human-readable description of the active pod filters
+func describePodFilter(ctx *parser.Context) string {
+	var parts []string
+	if ctx.PodStatus != "" {
+		parts = append(parts, strings.ReplaceAll(ctx.PodStatus, "-", " "))
+	}
+	if ctx.MinRestarts > 0 {
+		parts = append(parts, "restarted at least "+strconv.Itoa(ctx.MinRestarts)+" times")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatAge renders a timestamp as a short age like kubectl does (e.g. "5m", "3h", "2d")
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// pickPod resolves "newest pod of api", "the second api pod", "any ready api pod"
+// or "api on node X" to the name of one pod. The app and selector only choose
+// the pod, so they are cleared once it is picked.
+func pickPod(ctx *parser.Context) error {
+	if !ctx.HasPodPick() {
+		return replaceStalePod(ctx)
+	}
+
+	extraArgs := appendSelector(nil, labelSelector(ctx))
+	if ctx.Node != "" {
+		extraArgs = append(extraArgs, "--field-selector", "spec.nodeName="+ctx.Node)
+	}
+	if ctx.Namespace != "" {
+		extraArgs = append(extraArgs, "-n", ctx.Namespace)
+	}
+	description := describePodPick(ctx)
+
+	if ctx.DryRun {
+		// Dry runs stay offline: show how the pod would be picked
+		fmt.Printf("%s📋 DRY RUN: Would pick the %s from:%s\n", config.ColorYellow, description, config.ColorReset)
+		fmt.Printf("kubectl %s\n", strings.Join(append([]string{"get", "pods", "-o", "json"}, extraArgs...), " "))
+		ctx.PodName = "<" + description + ">"
+	} else {
+		list, err := getPodList(extraArgs)
+		if err != nil {
+			return err
+		}
+		picked, err := choosePod(list.Items, ctx)
+		if err != nil {
+			return fmt.Errorf("no %s: %w", description, err)
+		}
+		fmt.Printf("%s🎯 Picked %s as the %s%s\n", config.ColorCyan, picked.Metadata.Name, description, config.ColorReset)
+		ctx.PodName = picked.Metadata.Name
+	}
+
+	ctx.AppName = ""
+	ctx.Selector = ""
+	return nil
+}
+
+// replaceStalePod swaps a pod name that no longer exists, because its pod was
+// replaced since skube init or only part of the name was given, for a ready pod
+// of the workload that owned it
+func replaceStalePod(ctx *parser.Context) error {
+	if ctx.PodName == "" || ctx.PodOwner == "" {
+		return nil
+	}
+	kind, name, _ := strings.Cut(ctx.PodOwner, "/")
+
+	var nsArgs []string
+	if ctx.Namespace != "" {
+		nsArgs = []string{"-n", ctx.Namespace}
+	}
+
+	if ctx.DryRun {
+		// Dry runs stay offline: show how a replacement would be picked
+		fmt.Printf("%s📋 DRY RUN: If pod %s no longer exists, would pick a ready pod of %s %s from:%s\n",
+			config.ColorYellow, ctx.PodName, kind, name, config.ColorReset)
+		fmt.Printf("kubectl %s\n", strings.Join(append([]string{"get", "pods", "-o", "json"}, nsArgs...), " "))
+		return nil
+	}
+
+	args := append([]string{"get", "pod", ctx.PodName, "--ignore-not-found", "-o", "name"}, nsArgs...)
+	if output, err := execCommand("kubectl", args...).Output(); err == nil && strings.TrimSpace(string(output)) != "" {
+		return nil
+	}
+
+	list, err := getPodList(nsArgs)
+	if err != nil {
+		return err
+	}
+	var owned []pod
+	for _, p := range list.Items {
+		if p.ownedBy(kind, name) {
+			owned = append(owned, p)
+		}
+	}
+	picked, err := choosePod(owned, &parser.Context{PodPick: parser.PickReady})
+	if err != nil {
+		return fmt.Errorf("pod %s no longer exists and %s %s has no ready pod: %w", ctx.PodName, kind, name, err)
+	}
+
+	fmt.Printf("%s🔄 Pod %s no longer exists; using %s of %s %s%s\n",
+		config.ColorYellow, ctx.PodName, picked.Metadata.Name, kind, name, config.ColorReset)
+	ctx.PodName = picked.Metadata.Name
+	return nil
+}
+
+// ownedBy reports whether the pod belongs to the workload of kind and name. A
+// deployment owns its pods through a ReplicaSet named after it plus a pod template hash.
+func (p pod) ownedBy(kind string, name string) bool {
+	for _, owner := range p.Metadata.OwnerReferences {
+		if kind == parser.KwDeployment {
+			hash, ok := strings.CutPrefix(owner.Name, name+"-")
+			if owner.Kind == "ReplicaSet" && ok && hash != "" && !strings.Contains(hash, "-") {
+				return true
+			}
+		} else if strings.EqualFold(owner.Kind, kind) && owner.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// choosePod picks one of pods by readiness, position in name order or age
+func choosePod(pods []pod, ctx *parser.Context) (pod, error) {
+	if ctx.PodPick == parser.PickReady {
+		var ready []pod
+		for _, p := range pods {
+			if p.isReady() {
+				ready = append(ready, p)
+			}
+		}
+		if len(ready) == 0 && len(pods) > 0 {
+			return pod{}, fmt.Errorf("none of %d pods is ready", len(pods))
+		}
+		pods = ready
+	}
+	if len(pods) == 0 {
+		return pod{}, fmt.Errorf("no pods found")
+	}
+
+	// Name order matches the listing of `skube pods`
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Metadata.Name < pods[j].Metadata.Name })
+
+	if ctx.PodIndex > 0 {
+		if ctx.PodIndex > len(pods) {
+			return pod{}, fmt.Errorf("only %d pods found", len(pods))
+		}
+		return pods[ctx.PodIndex-1], nil
+	}
+
+	picked := pods[0]
+	for _, p := range pods[1:] {
+		created := p.Metadata.CreationTimestamp
+		if (ctx.PodPick == parser.PickNewest && created.After(picked.Metadata.CreationTimestamp)) ||
+			(ctx.PodPick == parser.PickOldest && created.Before(picked.Metadata.CreationTimestamp)) {
+			picked = p
+		}
+	}
+	return picked, nil
+}
+
+// isReady reports whether the pod's Ready condition is true
+func (p pod) isReady() bool {
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == "Ready" {
+			return cond.Status == "True"
+		}
+	}
+	return false
+}
+
+// describePodPick renders a pod pick, e.g. "second pod of app api on node n1"
+func describePodPick(ctx *parser.Context) string {
+	var b strings.Builder
+	switch {
+	case ctx.PodIndex > 0:
+		b.WriteString(ordinalSuffix(ctx.PodIndex) + " ")
+	case ctx.PodPick != "":
+		b.WriteString(ctx.PodPick + " ")
+	}
+	b.WriteString("pod")
+	if ctx.AppName != "" {
+		b.WriteString(" of app " + ctx.AppName)
+	} else if ctx.Selector != "" {
+		b.WriteString(" matching " + ctx.Selector)
+	}
+	if ctx.Node != "" {
+		b.WriteString(" on node " + ctx.Node)
+	}
+	return b.String()
+}
+
+// ordinalSuffix renders n as "1st", "2nd", "3rd", "4th"
+func ordinalSuffix(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,312 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+const podListFixture = `{"items":[
+ {"metadata":{"name":"api-1","namespace":"prod"},
+  "spec":{"nodeName":"node-a","containers":[{"name":"api"}]},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"False"}],
+   "containerStatuses":[{"name":"api","ready":false,"restartCount":7,
+    "state":{"waiting":{"reason":"CrashLoopBackOff"}},
+    "lastState":{"terminated":{"reason":"Error","exitCode":1}}}]}},
+ {"metadata":{"name":"api-2","namespace":"prod"},
+  "spec":{"nodeName":"node-b","containers":[{"name":"api"}]},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}],
+   "containerStatuses":[{"name":"api","ready":true,"restartCount":2,"state":{"running":{}}}]}},
+ {"metadata":{"name":"api-3","namespace":"prod"},
+  "spec":{"containers":[{"name":"api"}]},
+  "status":{"phase":"Pending","conditions":[{"type":"Ready","status":"False"}]}},
+ {"metadata":{"name":"api-4","namespace":"prod"},
+  "spec":{"containers":[{"name":"api"}]},
+  "status":{"phase":"Failed","reason":"Evicted"}}
+]}`
+
+func TestMatchesPodStatus(t *testing.T) {
+	var list podList
+	if err := json.Unmarshal([]byte(podListFixture), &list); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	tests := []struct {
+		status      string
+		minRestarts int
+		expected    []string
+	}{
+		{"crashing", 0, []string{"api-1"}},
+		{"pending", 0, []string{"api-3"}},
+		{"failed", 0, []string{"api-4"}},
+		{"evicted", 0, []string{"api-4"}},
+		{"not-ready", 0, []string{"api-1", "api-3", "api-4"}},
+		{"", 3, []string{"api-1"}},
+		{"", 1, []string{"api-1", "api-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			var got []string
+			for _, p := range list.Items {
+				if matchesPodStatus(p, tt.status, tt.minRestarts) {
+					got = append(got, p.Metadata.Name)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("matchesPodStatus(%q, %d) matched %v, want %v", tt.status, tt.minRestarts, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleFilteredPods(t *testing.T) {
+	execCommand = fakeExecCommandWithOutput(podListFixture)
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "pods", PodStatus: "crashing", Namespace: "prod"})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if !strings.Contains(output, "api-1") || !strings.Contains(output, "CrashLoopBackOff") {
+		t.Errorf("Expected crashing pod in output, got %q", output)
+	}
+	if strings.Contains(output, "api-2") {
+		t.Errorf("Expected healthy pod to be filtered out, got %q", output)
+	}
+}
+
+func TestHandleFilteredPodsAllNamespaces(t *testing.T) {
+	execCommand = fakeExecCommandWithOutput(podListFixture)
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "pods", PodStatus: "crashing", AllNamespaces: true})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if !strings.Contains(output, "NAMESPACE") || !strings.Contains(output, "prod") {
+		t.Errorf("Expected a namespace column, got %q", output)
+	}
+}
+
+const pickPodsFixture = `{"items":[
+ {"metadata":{"name":"api-b","creationTimestamp":"2026-01-02T00:00:00Z"},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"False"}]}},
+ {"metadata":{"name":"api-c","creationTimestamp":"2026-01-03T00:00:00Z"},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}},
+ {"metadata":{"name":"api-a","creationTimestamp":"2026-01-01T00:00:00Z"},
+  "status":{"phase":"Pending","conditions":[{"type":"Ready","status":"False"}]}}
+]}`
+
+func TestChoosePod(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+		err      string
+	}{
+		{"newest", &parser.Context{PodPick: parser.PickNewest}, "api-c", ""},
+		{"oldest", &parser.Context{PodPick: parser.PickOldest}, "api-a", ""},
+		{"second in name order", &parser.Context{PodIndex: 2}, "api-b", ""},
+		{"any ready", &parser.Context{PodPick: parser.PickReady}, "api-c", ""},
+		{"out of range", &parser.Context{PodIndex: 4}, "", "only 3 pods found"},
+		{"on node", &parser.Context{Node: "node-a"}, "api-a", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list podList
+			if err := json.Unmarshal([]byte(pickPodsFixture), &list); err != nil {
+				t.Fatalf("invalid fixture: %v", err)
+			}
+
+			picked, err := choosePod(list.Items, tt.ctx)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("choosePod returned error: %v", err)
+			}
+			if picked.Metadata.Name != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, picked.Metadata.Name)
+			}
+		})
+	}
+}
+
+func TestPickPod(t *testing.T) {
+	execCommand = fakeExecCommandWithOutput(pickPodsFixture)
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	ctx := &parser.Context{Command: "shell", AppName: "api", PodPick: parser.PickNewest, Namespace: "prod"}
+	err := ExecuteCommand(ctx)
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if ctx.PodName != "api-c" || ctx.AppName != "" {
+		t.Errorf("expected pod api-c to replace app api, got pod %q app %q", ctx.PodName, ctx.AppName)
+	}
+	if !strings.Contains(buf.String(), "Picked api-c as the newest pod of app api") {
+		t.Errorf("expected the picked pod to be shown, got %q", buf.String())
+	}
+}
+
+func TestPickPodDryRun(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "logs", AppName: "api", Node: "node-a", PodIndex: 2, DryRun: true})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"kubectl get pods -o json -l app=api --field-selector spec.nodeName=node-a",
+		"kubectl logs <2nd pod of app api on node node-a>",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output containing %q, got %q", expected, output)
+		}
+	}
+}
+
+const ownedPodsFixture = `{"items":[
+ {"metadata":{"name":"api-5f4d3c2b1-hjklm","ownerReferences":[{"kind":"ReplicaSet","name":"api-5f4d3c2b1"}]},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}},
+ {"metadata":{"name":"api-5f4d3c2b1-bcdfg","ownerReferences":[{"kind":"ReplicaSet","name":"api-5f4d3c2b1"}]},
+  "status":{"phase":"Pending","conditions":[{"type":"Ready","status":"False"}]}},
+ {"metadata":{"name":"api-gateway-6c7d8f9b2-qwrtz","ownerReferences":[{"kind":"ReplicaSet","name":"api-gateway-6c7d8f9b2"}]},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}},
+ {"metadata":{"name":"db-0","ownerReferences":[{"kind":"StatefulSet","name":"db"}]},
+  "status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}}
+]}`
+
+// fakeStalePodCommand mocks kubectl for a cluster where no pod by the requested
+// name exists any more and the namespace holds the pods of ownedPodsFixture
+func fakeStalePodCommand(command string, args ...string) *exec.Cmd {
+	if len(args) > 1 && args[0] == "get" && args[1] == "pod" {
+		// --ignore-not-found prints nothing for a missing pod
+		return fakeExecCommandWithOutput("\n")(command, args...)
+	}
+	if len(args) > 1 && args[0] == "get" && args[1] == "pods" {
+		return fakeExecCommandWithOutput(ownedPodsFixture)(command, args...)
+	}
+	return fakeExecCommand(command, args...)
+}
+
+func TestReplaceStalePod(t *testing.T) {
+	execCommand = fakeStalePodCommand
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		name     string
+		owner    string
+		expected string
+		message  string
+		err      string
+	}{
+		{"deployment", "deployment/api", "api-5f4d3c2b1-hjklm",
+			"Pod api-7d9f8b6c5-x2x4z no longer exists; using api-5f4d3c2b1-hjklm of deployment api", ""},
+		{"statefulset", "statefulset/db", "db-0", "using db-0 of statefulset db", ""},
+		{"no pods left", "deployment/web", "", "", "deployment web has no ready pod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			ctx := &parser.Context{Command: "shell", PodName: "api-7d9f8b6c5-x2x4z", PodOwner: tt.owner, Namespace: "prod"}
+			err := ExecuteCommand(ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteCommand returned error: %v", err)
+			}
+			if ctx.PodName != tt.expected {
+				t.Errorf("expected pod %s, got %s", tt.expected, ctx.PodName)
+			}
+			if !strings.Contains(buf.String(), tt.message) {
+				t.Errorf("expected output containing %q, got %q", tt.message, buf.String())
+			}
+			if !strings.Contains(buf.String(), "MOCK_EXEC: kubectl exec -it "+tt.expected) {
+				t.Errorf("expected a shell into %s, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestReplaceStalePodKeepsExistingPod(t *testing.T) {
+	execCommand = fakeExecCommandWithOutput("pod/api-7d9f8b6c5-x2x4z\n")
+	defer func() { execCommand = exec.Command }()
+
+	ctx := &parser.Context{Command: "logs", PodName: "api-7d9f8b6c5-x2x4z", PodOwner: "deployment/api"}
+	if err := replaceStalePod(ctx); err != nil {
+		t.Fatalf("replaceStalePod returned error: %v", err)
+	}
+	if ctx.PodName != "api-7d9f8b6c5-x2x4z" {
+		t.Errorf("expected the existing pod to be kept, got %s", ctx.PodName)
+	}
+}
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+// defaultContainerAnnotation names the container kubectl picks when none is given
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// crashedLastInstance reports whether every pod targeted by a logs command has a
+// container whose last instance terminated with a non-zero exit code. The current
+// instance of such a container has little or no output, so its previous logs are
+// what the user wants. Returns a description of the crash for display.
+func crashedLastInstance(ctx *parser.Context) (string, bool) {
+	var extraArgs []string
+	if ctx.AppName != "" || (ctx.PodName == "" && ctx.Selector != "") {
+		extraArgs = append(extraArgs, "-l", labelSelector(ctx))
+	} else if ctx.PodName != "" {
+		extraArgs = append(extraArgs, "--field-selector", "metadata.name="+ctx.PodName)
+	} else {
+		return "", false
+	}
+	if ctx.Namespace != "" {
+		extraArgs = append(extraArgs, "-n", ctx.Namespace)
+	}
+
+	list, err := getPodList(extraArgs)
+	if err != nil || len(list.Items) == 0 {
+		return "", false
+	}
+
+	var description string
+	for _, p := range list.Items {
+		cs := p.containerStatus(ctx.Container)
+		if cs == nil || cs.LastState.Terminated == nil || cs.LastState.Terminated.ExitCode == 0 {
+			return "", false
+		}
+		description = fmt.Sprintf("container %s of %s last exited with code %d", cs.Name, p.Metadata.Name, cs.LastState.Terminated.ExitCode)
+		if cs.LastState.Terminated.Reason != "" {
+			description += " (" + cs.LastState.Terminated.Reason + ")"
+		}
+	}
+	if len(list.Items) > 1 {
+		description = fmt.Sprintf("all %d pods crashed; %s", len(list.Items), description)
+	}
+	return description, true
+}
+
+// containerStatus returns the status of the named container, or of the
+// container kubectl logs would pick by default when name is empty
+func (p pod) containerStatus(name string) *containerStatus {
+	if name == "" {
+		name = p.Metadata.Annotations[defaultContainerAnnotation]
+	}
+	if name == "" && len(p.Spec.Containers) > 0 {
+		name = p.Spec.Containers[0].Name
+	}
+	for i := range p.Status.ContainerStatuses {
+		if p.Status.ContainerStatuses[i].Name == name {
+			return &p.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+func TestCrashedLastInstance(t *testing.T) {
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		name      string
+		output    string
+		ctx       *parser.Context
+		expected  bool
+		describes string
+	}{
+		{
+			name: "crashed pod",
+			output: `{"items":[{"metadata":{"name":"api-1"},"spec":{"containers":[{"name":"api"}]},
+			 "status":{"containerStatuses":[{"name":"api","lastState":{"terminated":{"reason":"Error","exitCode":1}}}]}}]}`,
+			ctx:       &parser.Context{Command: "logs", PodName: "api-1"},
+			expected:  true,
+			describes: "container api of api-1 last exited with code 1 (Error)",
+		},
+		{
+			name: "clean exit",
+			output: `{"items":[{"metadata":{"name":"api-1"},"spec":{"containers":[{"name":"api"}]},
+			 "status":{"containerStatuses":[{"name":"api","lastState":{"terminated":{"reason":"Completed","exitCode":0}}}]}}]}`,
+			ctx:      &parser.Context{Command: "logs", PodName: "api-1"},
+			expected: false,
+		},
+		{
+			name: "sidecar crashed but target container did not",
+			output: `{"items":[{"metadata":{"name":"api-1"},"spec":{"containers":[{"name":"api"},{"name":"proxy"}]},
+			 "status":{"containerStatuses":[{"name":"api","lastState":{}},
+			  {"name":"proxy","lastState":{"terminated":{"exitCode":137}}}]}}]}`,
+			ctx:      &parser.Context{Command: "logs", PodName: "api-1"},
+			expected: false,
+		},
+		{
+			name: "named sidecar crashed",
+			output: `{"items":[{"metadata":{"name":"api-1"},"spec":{"containers":[{"name":"api"},{"name":"proxy"}]},
+			 "status":{"containerStatuses":[{"name":"api","lastState":{}},
+			  {"name":"proxy","lastState":{"terminated":{"exitCode":137}}}]}}]}`,
+			ctx:       &parser.Context{Command: "logs", PodName: "api-1", Container: "proxy"},
+			expected:  true,
+			describes: "container proxy of api-1 last exited with code 137",
+		},
+		{
+			name:     "app with one healthy pod",
+			output:   podListFixture,
+			ctx:      &parser.Context{Command: "logs", AppName: "api"},
+			expected: false,
+		},
+		{
+			name:     "no pods",
+			output:   `{"items":[]}`,
+			ctx:      &parser.Context{Command: "logs", AppName: "api"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCommand = fakeExecCommandWithOutput(tt.output)
+
+			description, ok := crashedLastInstance(tt.ctx)
+			if ok != tt.expected {
+				t.Fatalf("crashedLastInstance() = %v, want %v", ok, tt.expected)
+			}
+			if !strings.Contains(description, tt.describes) {
+				t.Errorf("expected description containing %q, got %q", tt.describes, description)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// currentReplicas reads the desired replica count of ctx.DeploymentName from the cluster
+func currentReplicas(ctx *parser.Context) (int, error) {
+	args := []string{"get", workloadKind(ctx), ctx.DeploymentName, "-o", "jsonpath={.spec.replicas}"}
+	if ctx.Namespace != "" {
+		args = append(args, "-n", ctx.Namespace)
+	}
+	output, err := execCommand("kubectl", args...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read replicas of %s %s: %w", workloadKind(ctx), ctx.DeploymentName, err)
+	}
+	replicas, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected replica count for %s %s: %q", workloadKind(ctx), ctx.DeploymentName, strings.TrimSpace(string(output)))
+	}
+	return replicas, nil
+}
+
+// targetReplicas applies a relative scaling operation to the current replica count
+func targetReplicas(ctx *parser.Context, current int) (int, error) {
+	amount := 1
+	if ctx.Replicas != "" {
+		n, err := strconv.Atoi(ctx.Replicas)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid replica change %q", ctx.Replicas)
+		}
+		amount = n
+	}
+
+	switch ctx.ScaleOp {
+	case parser.ScaleUp:
+		return current + amount, nil
+	case parser.ScaleDown:
+		return max(current-amount, 0), nil
+	case parser.ScaleDouble:
+		return current * 2, nil
+	case parser.ScaleHalf:
+		// Round up so that halving never scales a running deployment to zero
+		return (current + 1) / 2, nil
+	case parser.ScaleBack:
+		previous, ok := config.PreviousReplicas(kubeContextName(), scaleNamespace(ctx), scaleHistoryName(ctx))
+		if !ok {
+			return 0, fmt.Errorf("no earlier replica count recorded for %s %s\nskube remembers the count each time it scales a %s", workloadKind(ctx), ctx.DeploymentName, workloadKind(ctx))
+		}
+		return previous, nil
+	}
+	return 0, fmt.Errorf("unknown scaling %q", ctx.ScaleOp)
+}
+
+// describeScaleOp renders a relative scaling operation, e.g. "up by 2" or "back"
+func describeScaleOp(ctx *parser.Context) string {
+	if (ctx.ScaleOp == parser.ScaleUp || ctx.ScaleOp == parser.ScaleDown) && ctx.Replicas != "" {
+		return ctx.ScaleOp + " by " + ctx.Replicas
+	}
+	return ctx.ScaleOp
+}
+
+// handleRelativeScale scales a deployment or statefulset relative to its current replica count
+func handleRelativeScale(ctx *parser.Context) error {
+	if ctx.DryRun {
+		// Dry runs stay offline: show the plan without the numbers
+		fmt.Printf("%s⚖️  Scaling %s %s %s%s\n", config.ColorYellow, workloadKind(ctx), ctx.DeploymentName, describeScaleOp(ctx), config.ColorReset)
+		return runKubectl(scaleArgs(ctx, "<"+describeScaleOp(ctx)+">"), true)
+	}
+
+	current, err := currentReplicas(ctx)
+	if err != nil {
+		return err
+	}
+
+	target, err := targetReplicas(ctx, current)
+	if err != nil {
+		return err
+	}
+	if target == current {
+		fmt.Printf("%s⚖️  Nothing to do: %s %s already has %d replicas%s\n", config.ColorGreen, workloadKind(ctx), ctx.DeploymentName, current, config.ColorReset)
+		return nil
+	}
+
+	fmt.Printf("%s⚖️  Scaling %s %s from %d to %d replicas%s\n", config.ColorYellow, workloadKind(ctx), ctx.DeploymentName, current, target, config.ColorReset)
+	if err := runKubectl(scaleArgs(ctx, strconv.Itoa(target)), false); err != nil {
+		return err
+	}
+	recordPreviousReplicas(ctx, current)
+	return nil
+}
+
+// scaleArgs builds the kubectl scale arguments for ctx.DeploymentName
+func scaleArgs(ctx *parser.Context, replicas string) []string {
+	kubectlArgs := []string{"scale", workloadKind(ctx), ctx.DeploymentName, "--replicas=" + replicas}
+	if ctx.Namespace != "" {
+		kubectlArgs = append(kubectlArgs, "-n", ctx.Namespace)
+	}
+	return kubectlArgs
+}
+
+// recordPreviousReplicas remembers the count before scaling for "scale <name> back".
+// Failing to record never fails the scale itself.
+func recordPreviousReplicas(ctx *parser.Context, replicas int) {
+	if err := config.RecordPreviousReplicas(kubeContextName(), scaleNamespace(ctx), scaleHistoryName(ctx), replicas); err != nil {
+		fmt.Printf("%s⚠️  Could not record replica count: %v%s\n", config.ColorYellow, err, config.ColorReset)
+	}
+}
+
+// scaleHistoryName names ctx.DeploymentName in the scale history; other
+// workloads are prefixed with their kind so they cannot clash with a deployment
+func scaleHistoryName(ctx *parser.Context) string {
+	if ctx.WorkloadKind != "" {
+		return ctx.WorkloadKind + "/" + ctx.DeploymentName
+	}
+	return ctx.DeploymentName
+}
+
+// scaleNamespace returns the namespace a scale acts in, so the history is kept
+// under the same key whether or not the command named it: ctx.Namespace, or
+// else the namespace of the current context
+func scaleNamespace(ctx *parser.Context) string {
+	if ctx.Namespace != "" {
+		return ctx.Namespace
+	}
+	output, err := execCommand("kubectl", "config", "view", "--minify", "-o", "jsonpath={..namespace}").Output()
+	if namespace := strings.TrimSpace(string(output)); err == nil && namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// kubeContextName returns the current kubectl context, or "" when it is unknown
+func kubeContextName() string {
+	kubeContext, _ := config.GetCurrentKubeContext()
+	return kubeContext
+}
@@ -0,0 +1,158 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+// fakeScaleCommand answers replica reads with *replicas, puts the current
+// context in namespace staging and mocks everything else
+func fakeScaleCommand(replicas *string) func(string, ...string) *exec.Cmd {
+	return func(command string, args ...string) *exec.Cmd {
+		if len(args) > 0 && args[0] == "get" {
+			return fakeExecCommandWithOutput(*replicas)(command, args...)
+		}
+		if len(args) > 0 && args[0] == "config" {
+			return fakeExecCommandWithOutput("staging")(command, args...)
+		}
+		return fakeExecCommand(command, args...)
+	}
+}
+
+func TestRelativeScale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LOCALAPPDATA", t.TempDir())
+	replicas := "4"
+	execCommand = fakeScaleCommand(&replicas)
+	defer func() { execCommand = exec.Command }()
+
+	// Steps share the recorded history, so they run in order
+	tests := []struct {
+		name     string
+		current  string
+		ctx      *parser.Context
+		expected []string
+		err      string
+	}{
+		{
+			name:    "back without history",
+			current: "4",
+			ctx:     &parser.Context{Command: "scale", DeploymentName: "api", ScaleOp: parser.ScaleBack, Namespace: "prod"},
+			err:     "no earlier replica count recorded",
+		},
+		{
+			name:     "up by 2",
+			current:  "4",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "api", ScaleOp: parser.ScaleUp, Replicas: "2", Namespace: "prod"},
+			expected: []string{"from 4 to 6 replicas", "MOCK_EXEC: kubectl scale deployment api --replicas=6 -n prod"},
+		},
+		{
+			name:     "back restores the recorded count",
+			current:  "6",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "api", ScaleOp: parser.ScaleBack, Namespace: "prod"},
+			expected: []string{"from 6 to 4 replicas", "--replicas=4"},
+		},
+		{
+			name:     "half rounds up",
+			current:  "5",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleHalf},
+			expected: []string{"from 5 to 3 replicas", "--replicas=3"},
+		},
+		{
+			name:     "double",
+			current:  "3",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleDouble},
+			expected: []string{"from 3 to 6 replicas"},
+		},
+		{
+			name:     "down never goes below zero",
+			current:  "2",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleDown, Replicas: "5"},
+			expected: []string{"from 2 to 0 replicas"},
+		},
+		{
+			name:     "back after absolute scale",
+			current:  "0",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleBack},
+			expected: []string{"from 0 to 2 replicas"},
+		},
+		{
+			name:     "no change",
+			current:  "1",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleHalf},
+			expected: []string{"already has 1 replicas"},
+		},
+		{
+			name:     "back finds history recorded without a namespace",
+			current:  "2",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "worker", ScaleOp: parser.ScaleBack, Namespace: "staging"},
+			expected: []string{"from 2 to 0 replicas"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas = tt.current
+
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteCommand returned error: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected output containing %q, got %q", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRelativeScaleDryRunOffline(t *testing.T) {
+	// The dry run shows the plan without reading the replica count
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		t.Errorf("dry run ran %s %s", command, strings.Join(args, " "))
+		return fakeExecCommand(command, args...)
+	}
+	defer func() { execCommand = exec.Command }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := ExecuteCommand(&parser.Context{Command: "scale", DeploymentName: "api", ScaleOp: parser.ScaleUp, Replicas: "2", DryRun: true})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("ExecuteCommand returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "kubectl scale deployment api --replicas=<up by 2>") {
+		t.Errorf("Expected the planned scale, got %q", buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
+)
+
+// workloadKind returns the kind of ctx.DeploymentName, which defaults to deployment
+func workloadKind(ctx *parser.Context) string {
+	if ctx.WorkloadKind != "" {
+		return ctx.WorkloadKind
+	}
+	return parser.KwDeployment
+}
+
+// checkWorkloadKind fails when the kind of ctx.DeploymentName is not one of kinds
+func checkWorkloadKind(ctx *parser.Context, action string, kinds ...string) error {
+	kind := workloadKind(ctx)
+	for _, k := range kinds {
+		if k == kind {
+			return nil
+		}
+	}
+
+	plural := make([]string, len(kinds))
+	for i, k := range kinds {
+		plural[i] = k + "s"
+	}
+	supported := strings.Join(plural, ", ")
+	if len(plural) > 1 {
+		supported = strings.Join(plural[:len(plural)-1], ", ") + " and " + plural[len(plural)-1]
+	}
+	return fmt.Errorf("cannot %s %s %s\nskube %s works on %s", action, kind, ctx.DeploymentName, action, supported)
+}
+
+// handleWorkloads lists statefulsets, daemonsets, jobs or cronjobs
+func handleWorkloads(ctx *parser.Context) error {
+	kubectlArgs := []string{"get", ctx.Command, "-o", "wide"}
+
+	kubectlArgs = appendNamespace(kubectlArgs, ctx)
+	kubectlArgs = appendSelector(kubectlArgs, ctx.Selector)
+
+	fmt.Printf("%s🚀 Listing %s%s\n", config.ColorCyan, ctx.Command, config.ColorReset)
+	return runKubectl(kubectlArgs, ctx.DryRun)
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/parser"
+)
+
+func TestWorkloadKinds(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		name     string
+		ctx      *parser.Context
+		expected string
+		err      string
+	}{
+		{
+			name:     "restart statefulset",
+			ctx:      &parser.Context{Command: "restart", DeploymentName: "db", WorkloadKind: parser.KwStatefulSet, Namespace: "prod"},
+			expected: "MOCK_EXEC: kubectl rollout restart statefulset db -n prod",
+		},
+		{
+			name:     "restart daemonset",
+			ctx:      &parser.Context{Command: "restart", DeploymentName: "fluentd", WorkloadKind: parser.KwDaemonSet},
+			expected: "MOCK_EXEC: kubectl rollout restart daemonset fluentd",
+		},
+		{
+			name: "restart job",
+			ctx:  &parser.Context{Command: "restart", DeploymentName: "migrate", WorkloadKind: parser.KwJob},
+			err:  "skube restart works on deployments, statefulsets and daemonsets",
+		},
+		{
+			name:     "scale statefulset",
+			ctx:      &parser.Context{Command: "scale", DeploymentName: "db", WorkloadKind: parser.KwStatefulSet, Replicas: "3", DryRun: true},
+			expected: "kubectl scale statefulset db --replicas=3",
+		},
+		{
+			name: "scale daemonset",
+			ctx:  &parser.Context{Command: "scale", DeploymentName: "fluentd", WorkloadKind: parser.KwDaemonSet, Replicas: "3"},
+			err:  "skube scale works on deployments and statefulsets",
+		},
+		{
+			name:     "rollback daemonset",
+			ctx:      &parser.Context{Command: "rollback", DeploymentName: "fluentd", WorkloadKind: parser.KwDaemonSet, Namespace: "logging"},
+			expected: "MOCK_EXEC: kubectl rollout undo daemonset fluentd -n logging",
+		},
+		{
+			name:     "rollout status statefulset",
+			ctx:      &parser.Context{Command: "rollout-status", DeploymentName: "db", WorkloadKind: parser.KwStatefulSet},
+			expected: "MOCK_EXEC: kubectl rollout status statefulset/db",
+		},
+		{
+			name:     "logs of job",
+			ctx:      &parser.Context{Command: "logs", DeploymentName: "migrate", WorkloadKind: parser.KwJob, Namespace: "prod", DryRun: true},
+			expected: "kubectl logs job/migrate -n prod",
+		},
+		{
+			name:     "logs of deployment",
+			ctx:      &parser.Context{Command: "logs", DeploymentName: "api", DryRun: true},
+			expected: "kubectl logs deployment/api",
+		},
+		{
+			name: "logs of cronjob",
+			ctx:  &parser.Context{Command: "logs", DeploymentName: "backup", WorkloadKind: parser.KwCronJob},
+			err:  "Read the logs of one of its jobs instead",
+		},
+		{
+			name:     "list cronjobs",
+			ctx:      &parser.Context{Command: "cronjobs", Namespace: "batch"},
+			expected: "MOCK_EXEC: kubectl get cronjobs -o wide -n batch",
+		},
+		{
+			name:     "list statefulsets everywhere",
+			ctx:      &parser.Context{Command: "statefulsets", AllNamespaces: true},
+			expected: "MOCK_EXEC: kubectl get statefulsets -o wide --all-namespaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := ExecuteCommand(tt.ctx)
+
+			w.Close()
+			os.Stdout = oldStdout
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			output := buf.String()
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteCommand returned error: %v", err)
+			}
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected output containing %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package help
 import (
 	"fmt"
 	"runtime/debug"
+	"sort"
 
 	"github.com/geminal/skube/internal/config"
 )
@@ -55,21 +56,24 @@ Examples:
   skube shell into pod backend-123
   skube in production shell into pod database-0`,
 
-	"restart": `Usage: skube restart <deployment|pod> <name> [in <namespace>]
+	"restart": `Usage: skube restart <deployment|statefulset|daemonset|pod> <name> [in <namespace>]
 
-Restart a resource. For deployments, it performs a rollout restart. For pods, it deletes the pod.
+Restart a resource. For deployments, statefulsets and daemonsets, it performs a rollout restart.
+For pods, it deletes the pod. Without a kind, a learned statefulset or daemonset name picks its kind.
 
 Examples:
   skube restart deployment backend
+  skube restart sts postgres
   skube restart pod worker-123`,
 
-	"scale": `Usage: skube scale deployment <name> to <N> [in <namespace>]
+	"scale": `Usage: skube scale <deployment|statefulset> <name> to <N> [in <namespace>]
 
-Scale a deployment to a specific number of replicas.
+Scale a deployment or statefulset to a specific number of replicas.
 
 Examples:
   skube scale deployment backend to 5
-  skube scale deployment worker to 0 in staging`,
+  skube scale deployment worker to 0 in staging
+  skube scale statefulset postgres to 3`,
 
 	"forward": `Usage: skube forward service <name> port <port> [in <namespace>]
 
@@ -118,12 +122,18 @@ func PrintHelp(args ...string) {
   %sswitch-ai%s   Switch between AI providers (Ollama/OpenAI)
   %sconfig-ai%s   Import AI config from JSON file
   %smodel%s       Show current AI model and provider configuration
+  %sexplain-parse%s Show how skube understands a command, without running it
+  %smacros%s      List the multi-step macros defined in your config
   %shelp%s        Show help message (try: skube help logs)
 
 %sRESOURCES:%s
   %snamespaces%s    Kubernetes namespaces (environments)
   %spods%s          Running pod instances
   %sdeployments%s   Deployment configurations
+  %sstatefulsets%s  Stateful workloads (sts)
+  %sdaemonsets%s    Per-node workloads (ds)
+  %sjobs%s          Run-to-completion workloads
+  %scronjobs%s      Scheduled jobs (cj)
   %sservices%s      Service endpoints
   %snodes%s         Cluster nodes
   %sconfigmaps%s    Configuration data (cm)
@@ -195,8 +205,14 @@ func PrintHelp(args ...string) {
 		config.ColorCyan, config.ColorReset, // switch-ai
 		config.ColorCyan, config.ColorReset, // config-ai
 		config.ColorCyan, config.ColorReset, // model
+		config.ColorCyan, config.ColorReset, // explain-parse
+		config.ColorCyan, config.ColorReset, // macros
 		config.ColorCyan, config.ColorReset, // help
 		config.ColorYellow, config.ColorReset,
+		config.ColorCyan, config.ColorReset, // statefulsets
+		config.ColorCyan, config.ColorReset, // daemonsets
+		config.ColorCyan, config.ColorReset, // jobs
+		config.ColorCyan, config.ColorReset, // cronjobs
 		config.ColorCyan, config.ColorReset,
 		config.ColorCyan, config.ColorReset,
 		config.ColorCyan, config.ColorReset,
@@ -248,3 +264,38 @@ func PrintHelp(args ...string) {
 
 	fmt.Print(help)
 }
+
+// PrintMacros lists the macros defined in the skube config
+func PrintMacros(macros map[string]*config.Macro) {
+	if len(macros) == 0 {
+		fmt.Printf("%sNo macros defined.%s Add a \"macros\" section to %s\n", config.ColorYellow, config.ColorReset, config.GetConfigPath())
+		return
+	}
+
+	names := make([]string, 0, len(macros))
+	for name := range macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("%sMacros:%s\n", config.ColorGreen, config.ColorReset)
+	for _, name := range names {
+		macro := macros[name]
+		usage := name
+		for _, param := range macro.Params {
+			usage += " <" + param + ">"
+		}
+		fmt.Printf("  %s%s%s", config.ColorCyan, usage, config.ColorReset)
+		if macro.Description != "" {
+			fmt.Printf("  %s", macro.Description)
+		}
+		fmt.Println()
+		for i, step := range macro.Steps {
+			fmt.Printf("    %d. %s", i+1, step.Run)
+			if step.OnError == "continue" {
+				fmt.Print(" (continues on error)")
+			}
+			fmt.Println()
+		}
+	}
+}
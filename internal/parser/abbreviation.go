@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// nameSegments splits a resource name into lowercase words the way the cluster's
+// naming convention joins them. Names that do not follow the convention, or a
+// mixed or unknown convention, are split at every separator and case change.
+func nameSegments(name string, convention string) []string {
+	var segments []string
+	switch convention {
+	case "hyphen":
+		segments = strings.Split(strings.ToLower(name), "-")
+	case "underscore":
+		segments = strings.Split(strings.ToLower(name), "_")
+	case "camelCase", "PascalCase":
+		segments = splitCamelCase(name)
+	}
+	if len(segments) > 1 {
+		return segments
+	}
+	return nameTokens(name)
+}
+
+// splitCamelCase splits "paymentService" or "PaymentService" into lowercase words
+func splitCamelCase(name string) []string {
+	var segments []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+			segments = append(segments, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	return append(segments, strings.ToLower(string(runes[start:])))
+}
+
+// compactName lowercases a typed name and drops its separators, so "a gw" abbreviates like "agw"
+func compactName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name))
+}
+
+// Abbreviates reports whether abbr is a short form of a name made of segments:
+// it must start with the first letter of the first segment, and every later
+// letter continues the current segment or starts a later one. This matches
+// initials ("ps" for payment-service), segment prefixes ("notif" for
+// notification-worker) and dropped letters ("agw" for api-gateway).
+func Abbreviates(abbr string, segments []string) bool {
+	abbr = strings.ToLower(abbr)
+	if len(abbr) < 2 || len(segments) == 0 || segments[0] == "" || abbr[0] != segments[0][0] {
+		return false
+	}
+	return abbreviatesFrom(abbr[1:], segments, 0, 1)
+}
+
+// abbreviatesFrom matches the rest of an abbreviation, positioned at offset
+// within segment seg
+func abbreviatesFrom(abbr string, segments []string, seg int, offset int) bool {
+	if abbr == "" {
+		return true
+	}
+
+	// Continue the current segment with a later letter of it
+	if i := strings.IndexByte(segments[seg][offset:], abbr[0]); i >= 0 {
+		if abbreviatesFrom(abbr[1:], segments, seg, offset+i+1) {
+			return true
+		}
+	}
+
+	// Or start a later segment
+	for next := seg + 1; next < len(segments); next++ {
+		if segments[next] != "" && segments[next][0] == abbr[0] && abbreviatesFrom(abbr[1:], segments, next, 1) {
+			return true
+		}
+	}
+	return false
+}
+
+// abbreviationMatches returns the learned resources of kind that name abbreviates,
+// split into segments by the learned naming convention
+func (r *ResourceResolver) abbreviationMatches(kind string, name string, namespace string) []Candidate {
+	abbr := compactName(name)
+	if abbr == "" {
+		return nil
+	}
+	var matches []Candidate
+
+	for _, entry := range r.index(kind).startingWith(abbr[0], namespace) {
+		if entry.lower == abbr || !isSubsequence(abbr, entry.lower) {
+			continue
+		}
+		if Abbreviates(abbr, nameSegments(entry.name, r.patterns.NamingConvention)) {
+			matches = append(matches, Candidate{Name: entry.name, Namespace: entry.namespace})
+		}
+	}
+
+	return matches
+}
+
+// isSubsequence reports whether the letters of abbr appear in s in order, which
+// every abbreviation of s satisfies
+func isSubsequence(abbr string, s string) bool {
+	i := 0
+	for j := 0; i < len(abbr) && j < len(s); j++ {
+		if abbr[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(abbr)
+}
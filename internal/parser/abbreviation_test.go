@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestAbbreviates(t *testing.T) {
+	tests := []struct {
+		abbr       string
+		name       string
+		convention string
+		expected   bool
+	}{
+		{"ps", "payment-service", "hyphen", true},
+		{"agw", "api-gateway", "hyphen", true},
+		{"notif", "notification-worker", "hyphen", true},
+		{"nw", "notification-worker", "hyphen", true},
+		{"ps", "paymentService", "camelCase", true},
+		{"ps", "payment_service", "underscore", true},
+		{"ps", "payment_service", "hyphen", true}, // names off the convention still split
+		{"sp", "payment-service", "hyphen", false},
+		{"gw", "api-gateway", "hyphen", false},
+		{"p", "payment-service", "hyphen", false},
+		{"psx", "payment-service", "hyphen", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.abbr+" "+tt.name, func(t *testing.T) {
+			if got := Abbreviates(tt.abbr, nameSegments(tt.name, tt.convention)); got != tt.expected {
+				t.Errorf("Abbreviates(%q, %q) = %v, want %v", tt.abbr, tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveAbbreviations(t *testing.T) {
+	r := &ResourceResolver{patterns: &config.ClusterPatterns{
+		Deployments: []string{
+			"prod/payment-service", "prod/api-gateway", "prod/notification-worker",
+			"prod/ps", "staging/pricing-sync",
+		},
+		Services:         []string{"prod/api-gateway"},
+		NamingConvention: "hyphen",
+	}}
+
+	tests := []struct {
+		name      string
+		input     string
+		namespace string
+		expected  string
+		method    string
+	}{
+		{"exact name wins over abbreviation", "ps", "prod", "ps", MatchExact},
+		{"initials", "ps", "staging", "pricing-sync", MatchAbbreviation},
+		{"dropped letters", "agw", "", "api-gateway", MatchAbbreviation},
+		{"segment prefix", "notif", "", "notification-worker", MatchAbbreviation},
+		{"substring wins over abbreviation", "gateway", "", "api-gateway", MatchSubstring},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := r.resolveAppName(tt.input, tt.namespace)
+			if res.Output != tt.expected || res.Method != tt.method {
+				t.Errorf("expected %q (%s), got %q (%s)", tt.expected, tt.method, res.Output, res.Method)
+			}
+		})
+	}
+
+	if got := r.ResolveServiceName("agw", "prod"); got != "api-gateway" {
+		t.Errorf("expected service api-gateway, got %q", got)
+	}
+
+	// Abbreviations rank below exact and substring candidates
+	candidates := r.ResolveCandidates(KwDeployment, "ps", "")
+	if len(candidates) < 3 || candidates[0].Name != "ps" || candidates[len(candidates)-1].Reason != MatchAbbreviation {
+		t.Fatalf("expected the exact match first and abbreviations last, got %+v", candidates)
+	}
+	for _, c := range candidates[1:] {
+		if c.Reason == MatchAbbreviation && c.Score >= scoreAbbreviation {
+			t.Errorf("expected abbreviation scores below %v, got %+v", scoreAbbreviation, c)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Scores of the match reasons that do not depend on how similar the names are.
+// Substring and fuzzy matches score their Similarity scaled between scoreSimilar
+// and scoreAbbreviation, and abbreviations their Similarity scaled below that.
+const (
+	scoreExact        = 1.0
+	scoreVariant      = 0.95
+	scorePattern      = 0.9
+	scoreSimilar      = 0.85
+	scoreAbbreviation = 0.4
+)
+
+// ResolveCandidates returns the learned resources of kind that input could name,
+// best first. kind is a deployment, statefulset, daemonset, job, cronjob, service,
+// pod or namespace; namespace limits the search when set. Each candidate carries
+// its Score and the Reason it matched, so callers can apply a confidence threshold
+// instead of accepting any fallback.
+func (r *ResourceResolver) ResolveCandidates(kind string, input string, namespace string) []Candidate {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	var entries []Candidate
+	for _, entry := range r.candidateEntries(kind) {
+		if namespace == "" || kind == KwNamespace || strings.EqualFold(entry.Namespace, namespace) {
+			entries = append(entries, entry)
+		}
+	}
+	return r.rankCandidates(kind, input, namespace, entries)
+}
+
+// rankCandidates scores entries against input and returns those that match, best
+// first. Disambiguation ranks the names it asks about with it, so the list a
+// user picks from is in the same order ResolveCandidates returns.
+func (r *ResourceResolver) rankCandidates(kind string, input string, namespace string, entries []Candidate) []Candidate {
+	variants := r.variants(input)
+
+	var candidates []Candidate
+	for _, entry := range entries {
+		if c, ok := r.scoreCandidate(kind, input, variants, entry, namespace); ok {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if candidates[i].Name != candidates[j].Name {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return candidates[i].Namespace < candidates[j].Namespace
+	})
+	return candidates
+}
+
+// candidateEntries returns the learned resources of kind as unscored candidates
+func (r *ResourceResolver) candidateEntries(kind string) []Candidate {
+	var entries []string
+	switch kind {
+	case "", KwDeployment:
+		entries = r.patterns.Deployments
+	case KwService:
+		entries = r.patterns.Services
+	case KwPod:
+		entries = r.patterns.Pods
+	case KwNamespace:
+		candidates := make([]Candidate, 0, len(r.patterns.Namespaces))
+		for _, ns := range r.patterns.Namespaces {
+			candidates = append(candidates, Candidate{Name: ns})
+		}
+		return candidates
+	default:
+		entries = r.patterns.Workloads[kind]
+	}
+
+	candidates := make([]Candidate, 0, len(entries))
+	for _, entry := range entries {
+		ns, name, ok := strings.Cut(entry, "/")
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, Candidate{Name: name, Namespace: ns})
+	}
+	return candidates
+}
+
+// scoreCandidate reports whether entry matches input, and how well. The
+// strongest reason wins: exact, then a naming variant, then the {app}-{namespace}
+// pattern, then substring and fuzzy matches ranked by Similarity, then abbreviations.
+func (r *ResourceResolver) scoreCandidate(kind, input string, variants []string, entry Candidate, namespace string) (Candidate, bool) {
+	name := entry.Name
+	nameLower := strings.ToLower(name)
+
+	if strings.EqualFold(name, input) {
+		entry.Score, entry.Reason = scoreExact, MatchExact
+		return entry, true
+	}
+	for _, variant := range variants {
+		if strings.EqualFold(name, variant) || NormalizeSpacesToHyphens(name) == NormalizeSpacesToHyphens(variant) {
+			entry.Score, entry.Reason = scoreVariant, MatchVariant
+			return entry, true
+		}
+	}
+	if (kind == "" || kind == KwDeployment) && namespace != "" && r.hasPattern("{app}-{namespace}") {
+		for _, variant := range variants {
+			if strings.EqualFold(name, variant+"-"+namespace) {
+				entry.Score, entry.Reason = scorePattern, MatchPattern
+				return entry, true
+			}
+		}
+	}
+
+	best := Candidate{}
+	for _, variant := range variants {
+		variantLower := strings.ToLower(variant)
+		similarity := Similarity(variant, name)
+		distance := DamerauLevenshteinDistance(variant, name)
+
+		reason := ""
+		switch {
+		case distance <= calculateThreshold(variant):
+			reason = MatchFuzzy
+		case len(variantLower) > 1 && strings.Contains(nameLower, variantLower):
+			reason = MatchSubstring
+		default:
+			continue
+		}
+
+		if score := scoreAbbreviation + (scoreSimilar-scoreAbbreviation)*similarity; score > best.Score {
+			best = entry
+			best.Score, best.Reason = score, reason
+			if reason == MatchFuzzy {
+				best.Distance = distance
+			}
+		}
+	}
+	if best.Reason != "" {
+		return best, true
+	}
+
+	// "agw" for api-gateway ranks below every substring or fuzzy match
+	if abbr := compactName(input); Abbreviates(abbr, nameSegments(name, r.patterns.NamingConvention)) {
+		entry.Score, entry.Reason = scoreAbbreviation*Similarity(abbr, name), MatchAbbreviation
+		return entry, true
+	}
+	return entry, false
+}
+
+// hasPattern reports whether the learned naming patterns include pattern
+func (r *ResourceResolver) hasPattern(pattern string) bool {
+	for _, p := range r.patterns.Patterns {
+		if p == pattern {
+			return true
+		}
+	}
+	return false
+}
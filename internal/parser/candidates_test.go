@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func newCandidateResolver() *ResourceResolver {
+	return &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces: []string{"prod", "staging"},
+		Deployments: []string{
+			"prod/billing", "staging/billing", "prod/billing-worker",
+			"prod/payment-service", "prod/api-gateway", "prod/web-prod",
+		},
+		Services: []string{"prod/billing"},
+		Patterns: []string{"{app}-{namespace}"},
+	}}
+}
+
+func TestResolveCandidatesReasons(t *testing.T) {
+	r := newCandidateResolver()
+
+	tests := []struct {
+		name      string
+		kind      string
+		input     string
+		namespace string
+		first     string
+		reason    string
+	}{
+		{"exact", KwDeployment, "billing", "prod", "billing", MatchExact},
+		{"naming variant", KwDeployment, "payment_service", "", "payment-service", MatchVariant},
+		{"pattern", KwDeployment, "web", "prod", "web-prod", MatchPattern},
+		{"substring", KwDeployment, "gateway", "", "api-gateway", MatchSubstring},
+		{"fuzzy transposition", KwDeployment, "bilinlg", "prod", "billing", MatchFuzzy},
+		{"service", KwService, "biling", "", "billing", MatchFuzzy},
+		{"namespace", KwNamespace, "stagign", "", "staging", MatchFuzzy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := r.ResolveCandidates(tt.kind, tt.input, tt.namespace)
+			if len(candidates) == 0 {
+				t.Fatalf("expected candidates for %q", tt.input)
+			}
+			if candidates[0].Name != tt.first || candidates[0].Reason != tt.reason {
+				t.Errorf("expected %s (%s) first, got %+v", tt.first, tt.reason, candidates)
+			}
+		})
+	}
+}
+
+func TestResolveCandidatesRanking(t *testing.T) {
+	r := newCandidateResolver()
+
+	candidates := r.ResolveCandidates(KwDeployment, "billing", "")
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Namespace+"/"+c.Name)
+	}
+	// Exact matches in both namespaces, then the weaker substring match
+	expected := []string{"prod/billing", "staging/billing", "prod/billing-worker"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+	if candidates[2].Score >= candidates[0].Score || candidates[2].Score <= 0 {
+		t.Errorf("expected the substring match to score between 0 and the exact match, got %v", candidates[2].Score)
+	}
+}
+
+func TestResolveCandidatesThreshold(t *testing.T) {
+	r := newCandidateResolver()
+
+	if candidates := r.ResolveCandidates(KwDeployment, "checkout", ""); len(candidates) != 0 {
+		t.Errorf("expected no candidates for an unknown name, got %+v", candidates)
+	}
+	if candidates := r.ResolveCandidates(KwDeployment, "billing", "staging"); len(candidates) != 1 {
+		t.Errorf("expected the namespace to limit candidates, got %+v", candidates)
+	}
+
+	// Callers keep only confident matches
+	var confident []Candidate
+	for _, c := range r.ResolveCandidates(KwDeployment, "bill", "") {
+		if c.Score >= 0.7 {
+			confident = append(confident, c)
+		}
+	}
+	if len(confident) != 0 {
+		t.Errorf("expected substring matches of a short prefix to stay below 0.7, got %+v", confident)
+	}
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// Candidate is a cluster resource that a typed name could refer to
+type Candidate struct {
+	Name      string
+	Namespace string
+	Distance  int
+
+	// Set by ResolveCandidates
+	Score  float64 // 1 for an exact match, lower for weaker matches
+	Reason string  // how the name matched: MatchExact, MatchVariant, MatchPattern, MatchSubstring, MatchFuzzy or MatchAbbreviation
+}
+
+// chooseCandidate picks one of several equally good candidates for input.
+// It is a variable to allow replacing the prompt in tests.
+var chooseCandidate = promptCandidate
+
+// chooseResolution resolves input against matches found by method. Matches
+// that all share one name are unambiguous; otherwise the user has to choose
+// among them, ranked best first as ResolveCandidates ranks them.
+func (r *ResourceResolver) chooseResolution(input, variant, kind, namespace, method string, matches []Candidate) Resolution {
+	res := Resolution{Input: input, Output: matches[0].Name, Method: method, Variant: variant, Distance: matches[0].Distance}
+
+	names := map[string]bool{}
+	for _, c := range matches {
+		names[c.Name] = true
+	}
+	if len(names) == 1 {
+		return res
+	}
+
+	matches = r.rankMatches(kind, input, namespace, matches)
+	chosen, err := chooseCandidate(input, kind, matches)
+	if err != nil {
+		return Resolution{Input: input, Output: variant, Method: MatchAmbiguous, Variant: variant, Candidates: matches, Err: err}
+	}
+	res.Output = chosen.Name
+	res.Namespace = chosen.Namespace
+	res.Distance = chosen.Distance
+	res.Candidates = matches
+	return res
+}
+
+// rankMatches orders the matches of a lookup by rankCandidates. The lookups and
+// the scoring agree on what matches, but any match the scoring rejects is kept
+// last rather than hidden from the user.
+func (r *ResourceResolver) rankMatches(kind, input, namespace string, matches []Candidate) []Candidate {
+	ranked := r.rankCandidates(kind, input, namespace, matches)
+	if len(ranked) == len(matches) {
+		return ranked
+	}
+
+	scored := map[Candidate]bool{}
+	for _, c := range ranked {
+		scored[Candidate{Name: c.Name, Namespace: c.Namespace}] = true
+	}
+	for _, c := range matches {
+		if !scored[Candidate{Name: c.Name, Namespace: c.Namespace}] {
+			ranked = append(ranked, c)
+		}
+	}
+	return ranked
+}
+
+// promptCandidate shows a numbered list of candidates and reads the user's pick.
+// Without a terminal it never guesses and returns an error listing the candidates.
+func promptCandidate(input, kind string, candidates []Candidate) (Candidate, error) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return Candidate{}, ambiguityError(input, kind, candidates)
+	}
+
+	fmt.Printf("%s🤔 %q matches several %ss:%s\n", config.ColorYellow, input, kind, config.ColorReset)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, c := range candidates {
+		fmt.Fprintf(w, "  %d)\t%s\t(%s)\n", i+1, c.Name, c.Namespace)
+	}
+	w.Flush()
+	fmt.Printf("Pick one [1-%d]: ", len(candidates))
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(response))
+	if err != nil || n < 1 || n > len(candidates) {
+		return Candidate{}, fmt.Errorf("no %s selected for %q", kind, input)
+	}
+	return candidates[n-1], nil
+}
+
+// ambiguityError lists the candidates so non-interactive callers can be more specific
+func ambiguityError(input, kind string, candidates []Candidate) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches several %ss:", input, kind)
+	for _, c := range candidates {
+		fmt.Fprintf(&b, "\n  %s (%s)", c.Name, c.Namespace)
+	}
+	b.WriteString("\nUse the full name or add a namespace")
+	return fmt.Errorf("%s", b.String())
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func newAmbiguousResolver() *ResourceResolver {
+	return &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces: []string{"prod", "staging"},
+		Deployments: []string{
+			"prod/api-gateway", "prod/api-server", "staging/api-worker",
+			"prod/billing", "staging/billing",
+			"prod/web-1", "prod/web-2",
+		},
+	}}
+}
+
+func TestResolveAmbiguousNonInteractive(t *testing.T) {
+	oldChoose := chooseCandidate
+	chooseCandidate = func(input, kind string, candidates []Candidate) (Candidate, error) {
+		return Candidate{}, ambiguityError(input, kind, candidates)
+	}
+	defer func() { chooseCandidate = oldChoose }()
+
+	r := newAmbiguousResolver()
+
+	tests := []struct {
+		name       string
+		input      string
+		namespace  string
+		wantErr    []string
+		wantOutput string
+	}{
+		{
+			name:    "segment matches across namespaces",
+			input:   "api",
+			wantErr: []string{"api-gateway (prod)", "api-server (prod)", "api-worker (staging)"},
+		},
+		{
+			name:    "fuzzy tie",
+			input:   "web-3",
+			wantErr: []string{"web-1 (prod)", "web-2 (prod)"},
+		},
+		{
+			name:       "namespace narrows to one",
+			input:      "api",
+			namespace:  "staging",
+			wantOutput: "api-worker",
+		},
+		{
+			name:       "same name in several namespaces is not ambiguous",
+			input:      "biling",
+			wantOutput: "billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := r.resolveAppName(tt.input, tt.namespace)
+			if len(tt.wantErr) == 0 {
+				if res.Err != nil || res.Output != tt.wantOutput {
+					t.Errorf("resolveAppName(%q) = %q, %v; want %q", tt.input, res.Output, res.Err, tt.wantOutput)
+				}
+				return
+			}
+			if res.Err == nil {
+				t.Fatalf("expected ambiguity error, got %q", res.Output)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(res.Err.Error(), want) {
+					t.Errorf("expected error to list %q, got %v", want, res.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveAmbiguousChoice(t *testing.T) {
+	oldChoose := chooseCandidate
+	var offered []Candidate
+	chooseCandidate = func(input, kind string, candidates []Candidate) (Candidate, error) {
+		offered = candidates
+		if kind != "deployment" {
+			return Candidate{}, fmt.Errorf("unexpected kind %s", kind)
+		}
+		for _, c := range candidates {
+			if c.Name == "api-worker" {
+				return c, nil
+			}
+		}
+		return Candidate{}, fmt.Errorf("api-worker not offered")
+	}
+	defer func() { chooseCandidate = oldChoose }()
+
+	r := newAmbiguousResolver()
+	res := r.resolveAppName("api", "")
+	if len(offered) != 3 {
+		t.Fatalf("expected 3 candidates offered, got %v", offered)
+	}
+	// Offered in the order ResolveCandidates ranks them
+	ranked := r.ResolveCandidates(KwDeployment, "api", "")
+	for i, c := range offered {
+		if c.Name != ranked[i].Name || c.Namespace != ranked[i].Namespace {
+			t.Errorf("expected candidate %d to be %s (%s) as ranked, got %s (%s)", i, ranked[i].Name, ranked[i].Namespace, c.Name, c.Namespace)
+		}
+	}
+	if res.Output != "api-worker" || res.Namespace != "staging" {
+		t.Errorf("expected api-worker in staging, got %q in %q", res.Output, res.Namespace)
+	}
+
+	ctx := &Context{Command: "logs", AppName: "api"}
+	ctx.AppName = applyResolution(ctx, nil, "app", res)
+	if ctx.Namespace != "staging" || ctx.ParseError != nil {
+		t.Errorf("expected namespace staging from the chosen candidate, got %q (%v)", ctx.Namespace, ctx.ParseError)
+	}
+}
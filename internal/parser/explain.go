@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Parsing stages reported by ExplainParse
+const (
+	StageVocabulary     = "vocabulary"
+	StageMemory         = "memory"
+	StageNamespaceFirst = "namespaceFirst"
+	StageStopWord       = "stopWord"
+	StageCommand        = "parseCommand"
+	StageResource       = "parseResource"
+	StageFlags          = "parseFlags"
+	StagePrepositions   = "parsePrepositions"
+	StageDefault        = "parseDefault"
+)
+
+// Resolver match methods reported by ExplainParse
+const (
+	MatchExact        = "exact"
+	MatchVariant      = "variant"
+	MatchFuzzy        = "fuzzy"
+	MatchPattern      = "pattern"
+	MatchSubstring    = "substring"
+	MatchAbbreviation = "abbreviation"
+	MatchFallback     = "fallback"
+	MatchAmbiguous    = "ambiguous"
+	MatchVocabulary   = "vocabulary"
+	MatchUnchanged    = "unchanged"
+)
+
+// TokenTrace records which parsing stage consumed one or more tokens
+// and which Context fields that stage set
+type TokenTrace struct {
+	Tokens  []string
+	Stage   string
+	Changes []string
+}
+
+// Resolution records how the resolver turned a parsed name into a cluster name
+type Resolution struct {
+	Field    string
+	Input    string
+	Output   string
+	Method   string
+	Variant  string // naming variant of the input that matched
+	Distance int    // edit distance for fuzzy matches
+
+	// Set when several resources matched and one had to be chosen
+	Namespace  string // namespace of the chosen candidate
+	Candidates []Candidate
+	Err        error // no candidate could be chosen
+}
+
+// String renders the resolution as a one-line explanation
+func (r Resolution) String() string {
+	if len(r.Candidates) > 0 && r.Err == nil {
+		return fmt.Sprintf("%s %q -> %q in %s (chosen from %d candidates)", r.Field, r.Input, r.Output, r.Namespace, len(r.Candidates))
+	}
+	switch r.Method {
+	case MatchExact:
+		if r.Variant != "" && r.Variant != r.Input {
+			return fmt.Sprintf("%s %q -> %q (exact, variant %q)", r.Field, r.Input, r.Output, r.Variant)
+		}
+		return fmt.Sprintf("%s %q -> %q (exact)", r.Field, r.Input, r.Output)
+	case MatchFuzzy:
+		return fmt.Sprintf("%s %q -> %q (fuzzy, distance %d)", r.Field, r.Input, r.Output, r.Distance)
+	case MatchPattern:
+		return fmt.Sprintf("%s %q -> %q (pattern {app}-{namespace})", r.Field, r.Input, r.Output)
+	case MatchSubstring:
+		return fmt.Sprintf("%s %q -> %q (substring)", r.Field, r.Input, r.Output)
+	case MatchAbbreviation:
+		return fmt.Sprintf("%s %q -> %q (abbreviation)", r.Field, r.Input, r.Output)
+	case MatchAmbiguous:
+		return fmt.Sprintf("%s %q is ambiguous (%d candidates)", r.Field, r.Input, len(r.Candidates))
+	case MatchVocabulary:
+		return fmt.Sprintf("%s %q -> %q (vocabulary)", r.Field, r.Input, r.Output)
+	case MatchFallback:
+		return fmt.Sprintf("%s %q -> %q (fallback variant, no cluster match)", r.Field, r.Input, r.Output)
+	default:
+		return fmt.Sprintf("%s %q kept as typed (no cluster match)", r.Field, r.Input)
+	}
+}
+
+// ParseTrace explains how ParseNaturalLanguage understood its input
+type ParseTrace struct {
+	Tokens         []TokenTrace
+	Resolutions    []Resolution
+	PatternsLoaded bool
+}
+
+// ExplainParse parses args like ParseNaturalLanguage and also returns a trace
+// of the stage that consumed each token and how each name was resolved
+func ExplainParse(args []string) (*Context, *ParseTrace) {
+	trace := &ParseTrace{}
+	ctx := parseNaturalLanguage(args, trace)
+	return ctx, trace
+}
+
+// record adds the tokens consumed by a stage and the fields it changed
+func (t *ParseTrace) record(tokens []string, stage string, before Context, after *Context) {
+	if t == nil || len(tokens) == 0 {
+		return
+	}
+	t.Tokens = append(t.Tokens, TokenTrace{
+		Tokens:  append([]string(nil), tokens...),
+		Stage:   stage,
+		Changes: contextChanges(before, *after),
+	})
+}
+
+// rewrite records a token replaced by the user vocabulary; an empty
+// replacement means the token was dropped as a stop word
+func (t *ParseTrace) rewrite(token, replacement string) {
+	if t == nil {
+		return
+	}
+	change := "-> " + replacement
+	if replacement == "" {
+		change = "ignored"
+	}
+	t.Tokens = append(t.Tokens, TokenTrace{Tokens: []string{token}, Stage: StageVocabulary, Changes: []string{change}})
+}
+
+// referenced records a phrase replaced by the target remembered from an earlier command
+func (t *ParseTrace) referenced(phrase, replacement string) {
+	if t == nil {
+		return
+	}
+	t.Tokens = append(t.Tokens, TokenTrace{Tokens: []string{phrase}, Stage: StageMemory, Changes: []string{"-> " + replacement}})
+}
+
+// resolved records a resolver decision and returns the resolved name
+func (t *ParseTrace) resolved(field string, res Resolution) string {
+	if t != nil {
+		res.Field = field
+		t.Resolutions = append(t.Resolutions, res)
+	}
+	return res.Output
+}
+
+// applyResolution records a resolver decision, carries over the namespace of a
+// chosen candidate and the first ambiguity error, and returns the resolved name
+func applyResolution(ctx *Context, trace *ParseTrace, field string, res Resolution) string {
+	if res.Err != nil && ctx.ParseError == nil {
+		ctx.ParseError = res.Err
+	}
+	if res.Namespace != "" && ctx.Namespace == "" {
+		ctx.Namespace = res.Namespace
+	}
+	return trace.resolved(field, res)
+}
+
+// contextChanges lists the Context fields that differ, as "Field=value"
+func contextChanges(before, after Context) []string {
+	var changes []string
+	b := reflect.ValueOf(before)
+	a := reflect.ValueOf(after)
+	for i := 0; i < a.NumField(); i++ {
+		if reflect.DeepEqual(b.Field(i).Interface(), a.Field(i).Interface()) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s=%v", a.Type().Field(i).Name, a.Field(i).Interface()))
+	}
+	return changes
+}
+
+// exactResolution describes an exact match of one of the input's naming variants
+func exactResolution(input, variant, match string) Resolution {
+	return Resolution{Input: input, Output: match, Method: MatchExact, Variant: variant}
+}
+
+// fuzzyResolution describes a fuzzy match, including its edit distance
+func fuzzyResolution(input, variant, match string) Resolution {
+	return Resolution{
+		Input:    input,
+		Output:   match,
+		Method:   MatchFuzzy,
+		Variant:  variant,
+		Distance: LevenshteinDistance(variant, match),
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestExplainParseStages(t *testing.T) {
+	ctx, trace := ExplainParse([]string{"logs", "of", "api", "in", "prod", "follow"})
+
+	if ctx.Command != "logs" || ctx.Namespace == "" || !ctx.Follow {
+		t.Fatalf("unexpected context: %+v", ctx)
+	}
+
+	var got []string
+	for _, tok := range trace.Tokens {
+		got = append(got, strings.Join(tok.Tokens, " ")+"="+tok.Stage)
+	}
+	expected := []string{
+		"logs=" + StageCommand,
+		"of api=" + StagePrepositions,
+		"in prod=" + StagePrepositions,
+		"follow=" + StageFlags,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected stages %v, got %v", expected, got)
+	}
+
+	if changes := trace.Tokens[1].Changes; len(changes) != 1 || changes[0] != "AppName=api" {
+		t.Errorf("expected \"of api\" to set AppName=api, got %v", changes)
+	}
+}
+
+func TestExplainParseMatchesParse(t *testing.T) {
+	args := []string{"in", "qa", "restart", "deployment", "web", "server", "--dry-run"}
+
+	ctx, trace := ExplainParse(args)
+	if !reflect.DeepEqual(ctx, ParseNaturalLanguage(args)) {
+		t.Errorf("ExplainParse context differs from ParseNaturalLanguage")
+	}
+	if trace.Tokens[0].Stage != StageNamespaceFirst {
+		t.Errorf("expected namespace-first stage, got %s", trace.Tokens[0].Stage)
+	}
+}
+
+func TestResolutionMethods(t *testing.T) {
+	r := &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces:  []string{"prod"},
+		Deployments: []string{"prod/web-server", "prod/billing-prod"},
+		Patterns:    []string{"{app}-{namespace}"},
+	}}
+
+	tests := []struct {
+		input    string
+		output   string
+		method   string
+		distance int
+	}{
+		{"web server", "web-server", MatchExact, 0},
+		{"web-servr", "web-server", MatchFuzzy, 1},
+		{"billing", "billing-prod", MatchPattern, 0},
+		{"payments api", "payments-api", MatchFallback, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			res := r.resolveAppName(tt.input, "prod")
+			if res.Output != tt.output || res.Method != tt.method || res.Distance != tt.distance {
+				t.Errorf("resolveAppName(%q) = %+v, want %s via %s (distance %d)", tt.input, res, tt.output, tt.method, tt.distance)
+			}
+		})
+	}
+}
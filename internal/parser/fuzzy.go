@@ -2,6 +2,7 @@ package parser
 
 import (
 	"strings"
+	"unicode"
 )
 
 // LevenshteinDistance calculates the edit distance between two strings.
@@ -182,3 +183,181 @@ func ContainsFuzzy(target string, candidates []string, maxDistance int) (string,
 
 	return "", false
 }
+
+// DamerauLevenshteinDistance is LevenshteinDistance that also counts swapping
+// two adjacent characters as a single edit ("deploymnet" -> "deployment").
+// It computes the optimal string alignment distance.
+func DamerauLevenshteinDistance(s1, s2 string) int {
+	r1 := []rune(strings.ToLower(s1))
+	r2 := []rune(strings.ToLower(s2))
+
+	if len(r1) == 0 {
+		return len(r2)
+	}
+	if len(r2) == 0 {
+		return len(r1)
+	}
+
+	// Transpositions look two rows back, so three rows are kept
+	prevPrev := make([]int, len(r2)+1)
+	prevRow := make([]int, len(r2)+1)
+	currRow := make([]int, len(r2)+1)
+
+	for j := 0; j <= len(r2); j++ {
+		prevRow[j] = j
+	}
+
+	for i := 1; i <= len(r1); i++ {
+		currRow[0] = i
+
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+
+			currRow[j] = min(
+				currRow[j-1]+1,    // insertion
+				prevRow[j]+1,      // deletion
+				prevRow[j-1]+cost, // substitution
+			)
+			if i > 1 && j > 1 && r1[i-1] == r2[j-2] && r1[i-2] == r2[j-1] && prevPrev[j-2]+1 < currRow[j] {
+				currRow[j] = prevPrev[j-2] + 1 // transposition
+			}
+		}
+
+		prevPrev, prevRow, currRow = prevRow, currRow, prevPrev
+	}
+
+	return prevRow[len(r2)]
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 (nothing
+// in common) to 1 (equal). Strings sharing a prefix score higher, which suits
+// resource names typed from their start.
+func JaroWinkler(s1, s2 string) float64 {
+	r1 := []rune(strings.ToLower(s1))
+	r2 := []rune(strings.ToLower(s2))
+
+	if len(r1) == 0 && len(r2) == 0 {
+		return 1
+	}
+	if len(r1) == 0 || len(r2) == 0 {
+		return 0
+	}
+
+	// Characters match when equal and no further apart than this window
+	window := max(len(r1), len(r2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1 := make([]bool, len(r1))
+	matched2 := make([]bool, len(r2))
+	matches := 0
+	for i := range r1 {
+		lo := max(0, i-window)
+		hi := i + window + 1
+		if hi > len(r2) {
+			hi = len(r2)
+		}
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && r1[i] == r2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Half the matched characters that appear in a different order
+	transpositions, j := 0, 0
+	for i := range r1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if r1[i] != r2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(r1)) + m/float64(len(r2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(r1) && prefix < len(r2) && r1[prefix] == r2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// nameTokens splits a resource name into lowercase words at separators and
+// camelCase boundaries: "paymentService-v2" -> ["payment", "service", "v2"]
+func nameTokens(name string) []string {
+	var tokens []string
+	var current []rune
+	runes := []rune(name)
+
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '_' || r == '.' || r == ' ' || r == '/':
+			flush()
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// TokenOverlap returns the share of distinct words the two names have in common
+// (Jaccard index), from 0 to 1, regardless of separators and word order
+func TokenOverlap(s1, s2 string) float64 {
+	t1 := map[string]bool{}
+	for _, t := range nameTokens(s1) {
+		t1[t] = true
+	}
+	t2 := map[string]bool{}
+	for _, t := range nameTokens(s2) {
+		t2[t] = true
+	}
+	if len(t1) == 0 && len(t2) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for t := range t1 {
+		if t2[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(t1)+len(t2)-shared)
+}
+
+// Similarity combines Damerau-Levenshtein, Jaro-Winkler and token overlap into
+// one score from 0 to 1 for how closely name matches what was typed
+func Similarity(input, name string) float64 {
+	longest := max(len([]rune(input)), len([]rune(name)))
+	if longest == 0 {
+		return 1
+	}
+	edit := 1 - float64(DamerauLevenshteinDistance(input, name))/float64(longest)
+	return 0.45*edit + 0.35*JaroWinkler(input, name) + 0.2*TokenOverlap(input, name)
+}
@@ -332,3 +332,66 @@ func TestGenerateNamingVariants(t *testing.T) {
 		})
 	}
 }
+
+// TestDamerauLevenshteinDistance tests that adjacent swaps count as one edit
+func TestDamerauLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		s1       string
+		s2       string
+		expected int
+	}{
+		{"identical strings", "hello", "hello", 0},
+		{"case insensitive", "Hello", "hello", 0},
+		{"one empty", "hello", "", 5},
+		{"transposition", "produciton", "production", 1},
+		{"transposition at start", "pai-gateway", "api-gateway", 1},
+		{"substitution", "hello", "hallo", 1},
+		{"insertion", "staging", "stagingg", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DamerauLevenshteinDistance(tt.s1, tt.s2)
+			if result != tt.expected {
+				t.Errorf("DamerauLevenshteinDistance(%q, %q) = %d, want %d", tt.s1, tt.s2, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestJaroWinkler tests the similarity bounds and the shared-prefix bonus
+func TestJaroWinkler(t *testing.T) {
+	if got := JaroWinkler("billing", "billing"); got != 1 {
+		t.Errorf("JaroWinkler of equal strings = %v, want 1", got)
+	}
+	if got := JaroWinkler("abc", "xyz"); got != 0 {
+		t.Errorf("JaroWinkler of disjoint strings = %v, want 0", got)
+	}
+	if got := JaroWinkler("martha", "marhta"); got < 0.96 || got > 0.962 {
+		t.Errorf("JaroWinkler(martha, marhta) = %v, want about 0.961", got)
+	}
+	if JaroWinkler("api-gw", "api-gateway") <= JaroWinkler("api-gw", "edge-api-gw-x") {
+		t.Error("expected a shared prefix to score higher")
+	}
+}
+
+// TestTokenOverlap tests word overlap across separators and camelCase
+func TestTokenOverlap(t *testing.T) {
+	tests := []struct {
+		s1       string
+		s2       string
+		expected float64
+	}{
+		{"payment-service", "paymentService", 1},
+		{"payment service", "service_payment", 1},
+		{"api", "api-gateway", 0.5},
+		{"web", "billing", 0},
+	}
+
+	for _, tt := range tests {
+		if got := TokenOverlap(tt.s1, tt.s2); got != tt.expected {
+			t.Errorf("TokenOverlap(%q, %q) = %v, want %v", tt.s1, tt.s2, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// placeholderPattern matches {param} placeholders in macro steps
+var placeholderPattern = regexp.MustCompile(`\{[A-Za-z0-9_-]+\}`)
+
+// ExpandMacro turns a macro invocation into one Context per step. args are the
+// words after the macro name: values for the macro's params in order, plus an
+// optional "in <namespace>" and --dry-run anywhere. A namespace given on the
+// command line fills {namespace}, or applies to steps that name no namespace.
+// Steps are parsed in order, so "it" in a step refers to the step before it.
+func ExpandMacro(name string, macro *config.Macro, args []string) ([]*Context, error) {
+	if isBuiltinWord(strings.ToLower(name)) {
+		return nil, fmt.Errorf("macro %q collides with a built-in word; rename it", name)
+	}
+	if len(macro.Steps) == 0 {
+		return nil, fmt.Errorf("macro %q has no steps", name)
+	}
+
+	var values []string
+	namespace := ""
+	dryRun := false
+	for i := 0; i < len(args); i++ {
+		switch word := strings.ToLower(args[i]); {
+		case word == "--dry-run":
+			dryRun = true
+		case (word == PrepIn || word == "-n" || word == "--namespace") && i+1 < len(args):
+			namespace = args[i+1]
+			i++
+		default:
+			values = append(values, args[i])
+		}
+	}
+
+	usage := macroUsage(name, macro)
+	if len(values) < len(macro.Params) {
+		return nil, fmt.Errorf("macro %s needs %s\nUsage: %s", name, strings.Join(macro.Params, ", "), usage)
+	}
+	if len(macro.Params) == 0 && len(values) > 0 {
+		return nil, fmt.Errorf("macro %s takes no parameters\nUsage: %s", name, usage)
+	}
+
+	replacements := []string{}
+	for i, param := range macro.Params {
+		value := values[i]
+		if i == len(macro.Params)-1 {
+			// The last parameter takes the remaining words: "deploy-check web server"
+			value = strings.Join(values[i:], " ")
+		}
+		replacements = append(replacements, "{"+param+"}", value)
+	}
+	if namespace != "" {
+		replacements = append(replacements, "{namespace}", namespace)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	var steps []*Context
+	mem := getMemory()
+	for i, step := range macro.Steps {
+		run := replacer.Replace(step.Run)
+		if placeholder := placeholderPattern.FindString(run); placeholder != "" {
+			if placeholder == "{namespace}" {
+				return nil, fmt.Errorf("macro %s step %d needs a namespace\nUsage: %s", name, i+1, usage)
+			}
+			return nil, fmt.Errorf("macro %s step %d uses unknown placeholder %s", name, i+1, placeholder)
+		}
+
+		ctx := parseTokens(tokenize([]string{run}), mem, namespace, nil)
+		if target := ctx.SessionTarget(); target != nil {
+			mem = target
+		}
+		ctx.DryRun = ctx.DryRun || dryRun
+		ctx.ContinueOnError = step.OnError == "continue"
+		steps = append(steps, ctx)
+	}
+
+	return steps, nil
+}
+
+// ValidateMacros checks that macro names do not shadow built-in words and
+// that every placeholder in a step is a declared parameter or {namespace}
+func ValidateMacros(macros map[string]*config.Macro) error {
+	names := make([]string, 0, len(macros))
+	for name := range macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		macro := macros[name]
+		if isBuiltinWord(strings.ToLower(name)) {
+			problems = append(problems, fmt.Errorf("macro %q collides with a built-in word", name))
+		}
+		if macro == nil || len(macro.Steps) == 0 {
+			problems = append(problems, fmt.Errorf("macro %q has no steps", name))
+			continue
+		}
+
+		declared := map[string]bool{"{namespace}": true}
+		for _, param := range macro.Params {
+			declared["{"+param+"}"] = true
+		}
+		for i, step := range macro.Steps {
+			for _, placeholder := range placeholderPattern.FindAllString(step.Run, -1) {
+				if !declared[placeholder] {
+					problems = append(problems, fmt.Errorf("macro %q step %d uses undeclared placeholder %s", name, i+1, placeholder))
+				}
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// macroUsage renders how to invoke a macro, e.g. "skube deploy-check <app> [in <namespace>]"
+func macroUsage(name string, macro *config.Macro) string {
+	usage := "skube " + name
+	for _, param := range macro.Params {
+		usage += " <" + param + ">"
+	}
+	return usage + " [in <namespace>]"
+}
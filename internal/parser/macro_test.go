@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+const macroConfigFixture = `{
+  "deploy-check": {
+    "description": "Rollout, recent logs and warnings for an app",
+    "params": ["app"],
+    "steps": [
+      "rollout status of {app}",
+      {"run": "logs of {app} get last 50", "on_error": "continue"},
+      "warning events of {app}"
+    ]
+  },
+  "ns-health": {
+    "steps": ["pods crashing in {namespace}", "warning events in {namespace}"]
+  }
+}`
+
+func loadMacroFixture(t *testing.T) map[string]*config.Macro {
+	t.Helper()
+	var macros map[string]*config.Macro
+	if err := json.Unmarshal([]byte(macroConfigFixture), &macros); err != nil {
+		t.Fatalf("invalid macro fixture: %v", err)
+	}
+	return macros
+}
+
+func TestExpandMacro(t *testing.T) {
+	macros := loadMacroFixture(t)
+
+	steps, err := ExpandMacro("deploy-check", macros["deploy-check"], []string{"billing", "in", "prod", "--dry-run"})
+	if err != nil {
+		t.Fatalf("ExpandMacro returned error: %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+
+	expected := []Context{
+		{Command: CmdRolloutStatus, AppName: "billing", Namespace: "prod", DryRun: true},
+		{Command: CmdLogs, AppName: "billing", Namespace: "prod", TailLines: 50, DryRun: true, ContinueOnError: true},
+		{Command: "events", AppName: "billing", Namespace: "prod", EventType: "Warning", DryRun: true},
+	}
+	for i, want := range expected {
+		got := *steps[i]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("step %d = %+v, want %+v", i+1, got, want)
+		}
+	}
+}
+
+func TestExpandMacroNamespacePlaceholder(t *testing.T) {
+	macros := loadMacroFixture(t)
+
+	steps, err := ExpandMacro("ns-health", macros["ns-health"], []string{"-n", "qa"})
+	if err != nil {
+		t.Fatalf("ExpandMacro returned error: %v", err)
+	}
+	if steps[0].Command != "pods" || steps[0].PodStatus != "crashing" || steps[0].Namespace != "qa" {
+		t.Errorf("unexpected first step: %+v", *steps[0])
+	}
+
+	if _, err := ExpandMacro("ns-health", macros["ns-health"], nil); err == nil || !strings.Contains(err.Error(), "needs a namespace") {
+		t.Errorf("expected missing namespace error, got %v", err)
+	}
+}
+
+func TestExpandMacroInOrder(t *testing.T) {
+	useMemory(t, &config.SessionTarget{AppName: "payments", Namespace: "qa"})
+	macro := &config.Macro{
+		Params: []string{"app"},
+		Steps: []config.MacroStep{
+			{Run: "logs of {app}"},
+			{Run: "warning events for that app"},
+			{Run: "pods in kube-system"},
+		},
+	}
+
+	steps, err := ExpandMacro("triage", macro, []string{"billing", "in", "prod"})
+	if err != nil {
+		t.Fatalf("ExpandMacro returned error: %v", err)
+	}
+	if steps[1].ParseError != nil || steps[1].AppName != "billing" || steps[1].Namespace != "prod" {
+		t.Errorf("expected the second step to refer to billing in prod, got %+v", *steps[1])
+	}
+	if steps[2].ParseError != nil || steps[2].Namespace != "kube-system" {
+		t.Errorf("expected the step's own namespace to win, got %+v", *steps[2])
+	}
+}
+
+func TestExpandMacroErrors(t *testing.T) {
+	macros := loadMacroFixture(t)
+
+	tests := []struct {
+		name    string
+		macro   string
+		args    []string
+		wantErr string
+	}{
+		{"missing param", "deploy-check", []string{"in", "prod"}, "needs app"},
+		{"unexpected param", "ns-health", []string{"billing", "in", "prod"}, "takes no parameters"},
+		{"built-in name", "logs", []string{"billing"}, "collides with a built-in word"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			macro := macros[tt.macro]
+			if macro == nil {
+				macro = macros["deploy-check"]
+			}
+			_, err := ExpandMacro(tt.macro, macro, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateMacros(t *testing.T) {
+	macros := loadMacroFixture(t)
+	if err := ValidateMacros(macros); err != nil {
+		t.Errorf("expected fixture to be valid, got %v", err)
+	}
+
+	macros["restart"] = &config.Macro{Steps: []config.MacroStep{{Run: "logs of {app}"}}}
+	err := ValidateMacros(macros)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{`macro "restart" collides`, "undeclared placeholder {app}"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+
+	var step config.MacroStep
+	if err := json.Unmarshal([]byte(`{"run": "pods", "on_error": "retry"}`), &step); err == nil {
+		t.Error("expected invalid on_error to be rejected")
+	}
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+var (
+	memory     *config.SessionTarget
+	memoryOnce sync.Once
+)
+
+// getMemory returns the target of the last command in this terminal session,
+// loading it on first use, or nil when there is none
+func getMemory() *config.SessionTarget {
+	memoryOnce.Do(func() {
+		memory, _ = config.LoadSessionTarget()
+	})
+	return memory
+}
+
+// SessionTarget returns the target ctx acts on, to be saved once the command has
+// run so later commands in the same terminal session can refer to it. It returns
+// nil for commands without a target, such as listings, and for failed parses.
+func (ctx *Context) SessionTarget() *config.SessionTarget {
+	if ctx.ParseError != nil || (ctx.AppName == "" && ctx.PodName == "" && ctx.DeploymentName == "" && ctx.ServiceName == "") {
+		return nil
+	}
+	return &config.SessionTarget{
+		Namespace:      ctx.Namespace,
+		AppName:        ctx.AppName,
+		PodName:        ctx.PodName,
+		DeploymentName: ctx.DeploymentName,
+		WorkloadKind:   ctx.WorkloadKind,
+		ServiceName:    ctx.ServiceName,
+	}
+}
+
+// referenceWords start a reference to an earlier target, as in "that app" or "same namespace"
+var referenceWords = map[string]bool{"that": true, "this": true, "same": true}
+
+// referenceNouns maps the noun after a reference word to the kind of target it
+// names; "" stands for whatever the last command acted on
+var referenceNouns = map[string]string{
+	KwApp: KwApp, KwPod: KwPod, KwDeployment: KwDeployment, KwService: KwService, "svc": KwService,
+	"one": "", "workload": "", "resource": "",
+	KwNamespace: KwNamespace, "ns": KwNamespace,
+}
+
+// targetPrepositions introduce the target of a command, as in "logs of it"
+var targetPrepositions = map[string]bool{
+	PrepOf: true, PrepFrom: true, PrepTo: true, PrepInto: true, "for": true, "on": true,
+}
+
+// namesTarget reports whether a bare "it" following prev stands where a command
+// takes its target: right after the command word or a preposition such as "of".
+// "before it crashed" or "search it" leave it alone.
+func namesTarget(prev []token) bool {
+	for i := len(prev) - 1; i >= 0; i-- {
+		word := strings.ToLower(prev[i].text)
+		if stopWords[word] && !targetPrepositions[word] {
+			continue
+		}
+		_, command := commandAliases[word]
+		return !prev[i].quoted && (command || targetPrepositions[word])
+	}
+	return false
+}
+
+// rewriteReferences replaces "it", "that app", "same namespace" and similar phrases with
+// mem, the target of the last command, noting each substitution in ctx.Notes. Quoted
+// phrases and search terms are never references.
+// It reports whether a target, not just a namespace, was substituted.
+func rewriteReferences(tokens []token, mem *config.SessionTarget, ctx *Context, trace *ParseTrace) ([]string, bool) {
+	args := texts(tokens)
+	rewritten := make([]string, 0, len(args))
+	usedTarget := false
+
+	for i := 0; i < len(args); i++ {
+		word := strings.ToLower(args[i])
+		kind, consumed := "", 0
+		switch {
+		case isLiteral(tokens[:i], tokens[i]):
+		case word == "it":
+			if namesTarget(tokens[:i]) {
+				consumed = 1
+			}
+		case referenceWords[word] && i+1 < len(args) && !tokens[i+1].quoted:
+			if noun, ok := referenceNouns[strings.ToLower(args[i+1])]; ok {
+				kind, consumed = noun, 2
+			}
+		}
+		if consumed == 0 {
+			rewritten = append(rewritten, args[i])
+			continue
+		}
+
+		phrase := strings.Join(args[i:i+consumed], " ")
+		i += consumed - 1
+
+		replacement := referenceReplacement(mem, kind)
+		if replacement == nil {
+			if ctx.ParseError == nil {
+				ctx.ParseError = fmt.Errorf("nothing to refer to with %q: no earlier command in this terminal session named one\nName the resource instead", phrase)
+			}
+			continue
+		}
+		if kind == KwNamespace {
+			// "events in the same namespace" already has its preposition
+			if !followsNamespacePreposition(rewritten) {
+				replacement = append([]string{PrepIn}, replacement...)
+			}
+		} else {
+			usedTarget = true
+		}
+
+		ctx.Notes = append(ctx.Notes, fmt.Sprintf("%q → %s", phrase, strings.Join(replacement, " ")))
+		trace.referenced(phrase, strings.Join(replacement, " "))
+		rewritten = append(rewritten, replacement...)
+	}
+	return rewritten, usedTarget
+}
+
+// referenceReplacement returns the words naming the remembered target of kind,
+// or nil when the memory holds no such target
+func referenceReplacement(mem *config.SessionTarget, kind string) []string {
+	if mem == nil {
+		return nil
+	}
+
+	workload := func() []string {
+		if mem.WorkloadKind != "" {
+			return []string{mem.WorkloadKind, mem.DeploymentName}
+		}
+		return []string{KwDeployment, mem.DeploymentName}
+	}
+
+	switch kind {
+	case KwNamespace:
+		if mem.Namespace != "" {
+			return []string{mem.Namespace}
+		}
+	case KwApp:
+		if mem.AppName != "" {
+			return []string{KwApp, mem.AppName}
+		}
+		if mem.DeploymentName != "" && mem.WorkloadKind == "" {
+			return []string{KwApp, mem.DeploymentName}
+		}
+	case KwPod:
+		if mem.PodName != "" {
+			return []string{KwPod, mem.PodName}
+		}
+	case KwDeployment:
+		if mem.DeploymentName != "" {
+			return workload()
+		}
+		if mem.AppName != "" {
+			return []string{KwDeployment, mem.AppName}
+		}
+	case KwService:
+		if mem.ServiceName != "" {
+			return []string{KwService, mem.ServiceName}
+		}
+		if mem.AppName != "" {
+			return []string{KwService, mem.AppName}
+		}
+	default:
+		switch {
+		case mem.DeploymentName != "":
+			return workload()
+		case mem.PodName != "":
+			return []string{KwPod, mem.PodName}
+		case mem.AppName != "":
+			return []string{KwApp, mem.AppName}
+		case mem.ServiceName != "":
+			return []string{KwService, mem.ServiceName}
+		}
+	}
+	return nil
+}
+
+// followsNamespacePreposition reports whether the last word of args, ignoring
+// stop words, introduces a namespace
+func followsNamespacePreposition(args []string) bool {
+	for i := len(args) - 1; i >= 0; i-- {
+		word := strings.ToLower(args[i])
+		if stopWords[word] {
+			continue
+		}
+		return word == PrepIn || word == PrepFrom || word == PrepInto
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// useMemory replaces the remembered session target for the duration of a test
+func useMemory(t *testing.T, target *config.SessionTarget) {
+	t.Helper()
+	memoryOnce.Do(func() {})
+	oldMemory := memory
+	memory = target
+	t.Cleanup(func() { memory = oldMemory })
+}
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		memory   *config.SessionTarget
+		args     []string
+		expected Context
+	}{
+		{
+			name:   "restart it",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"restart", "it"},
+			expected: Context{Command: CmdRestart, AppName: "billing", Namespace: "prod",
+				Notes: []string{`"it" → app billing`, "namespace prod from the last command"}},
+		},
+		{
+			name:   "events for that app",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"events", "for", "that", "app"},
+			expected: Context{Command: "events", AppName: "billing", Namespace: "prod",
+				Notes: []string{`"that app" → app billing`, "namespace prod from the last command"}},
+		},
+		{
+			name:   "explicit namespace wins",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"logs", "from", "it", "in", "staging"},
+			expected: Context{Command: CmdLogs, AppName: "billing", Namespace: "staging",
+				Notes: []string{`"it" → app billing`}},
+		},
+		{
+			name:   "workload kind is kept",
+			memory: &config.SessionTarget{DeploymentName: "postgres", WorkloadKind: KwStatefulSet, Namespace: "db"},
+			args:   []string{"scale", "it", "to", "3"},
+			expected: Context{Command: CmdScale, DeploymentName: "postgres", WorkloadKind: KwStatefulSet, Replicas: "3", Namespace: "db",
+				Notes: []string{`"it" → statefulset postgres`, "namespace db from the last command"}},
+		},
+		{
+			name:   "same namespace",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"pods", "in", "the", "same", "namespace"},
+			expected: Context{Command: "pods", Namespace: "prod",
+				Notes: []string{`"same namespace" → prod`}},
+		},
+		{
+			name:   "same namespace without preposition",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"events", "same", "namespace"},
+			expected: Context{Command: "events", Namespace: "prod",
+				Notes: []string{`"same namespace" → in prod`}},
+		},
+		{
+			name:     "before it crashed is no reference",
+			memory:   &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:     []string{"logs", "from", "pod", "api-1", "before", "it", "crashed"},
+			expected: Context{Command: CmdLogs, PodName: "api-1", Previous: true},
+		},
+		{
+			name:     "search for it is no reference",
+			memory:   &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:     []string{"logs", "of", "api", "grep", "-i", "it"},
+			expected: Context{Command: CmdLogs, AppName: "api", SearchTerm: "it", IgnoreCase: true},
+		},
+		{
+			name:     "quoted it is no reference",
+			memory:   &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:     []string{`logs of api search "it"`},
+			expected: Context{Command: CmdLogs, AppName: "api", SearchTerm: "it"},
+		},
+		{
+			name:   "it after a preposition",
+			memory: &config.SessionTarget{AppName: "billing", Namespace: "prod"},
+			args:   []string{"events", "for", "it"},
+			expected: Context{Command: "events", AppName: "billing", Namespace: "prod",
+				Notes: []string{`"it" → app billing`, "namespace prod from the last command"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemory(t, tt.memory)
+
+			ctx := ParseNaturalLanguage(tt.args)
+			if !reflect.DeepEqual(*ctx, tt.expected) {
+				t.Errorf("ParseNaturalLanguage(%v) =\n%+v\nwant\n%+v", tt.args, *ctx, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseReferenceWithoutMemory(t *testing.T) {
+	useMemory(t, nil)
+
+	ctx := ParseNaturalLanguage([]string{"restart", "it"})
+	if ctx.ParseError == nil || !strings.Contains(ctx.ParseError.Error(), `nothing to refer to with "it"`) {
+		t.Fatalf("expected an error for a reference without memory, got %v", ctx.ParseError)
+	}
+
+	useMemory(t, &config.SessionTarget{AppName: "billing"})
+	ctx = ParseNaturalLanguage([]string{"shell", "into", "that", "pod"})
+	if ctx.ParseError == nil {
+		t.Errorf("expected an error when the memory has no pod, got %+v", ctx)
+	}
+}
+
+func TestRememberAcrossSequence(t *testing.T) {
+	useMemory(t, nil)
+
+	steps := ParseSequence([]string{"logs", "from", "billing", "in", "prod", "then", "restart", "it"})
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+	if steps[1].AppName != "billing" || steps[1].Namespace != "prod" {
+		t.Errorf("expected the second step to restart billing in prod, got %+v", steps[1])
+	}
+
+	target := steps[1].SessionTarget()
+	if target == nil || target.AppName != "billing" || target.Namespace != "prod" {
+		t.Errorf("expected billing in prod as the target to remember, got %+v", target)
+	}
+	if memory != nil {
+		t.Errorf("expected parsing to leave the session memory alone, got %+v", memory)
+	}
+}
+
+func TestParsingLeavesMemoryAlone(t *testing.T) {
+	remembered := &config.SessionTarget{AppName: "billing", Namespace: "prod"}
+	useMemory(t, remembered)
+
+	ParseNaturalLanguage([]string{"logs", "from", "api", "in", "staging"})
+	ExplainParse([]string{"restart", "worker"})
+
+	if memory != remembered {
+		t.Errorf("expected the session memory to stay %+v, got %+v", remembered, memory)
+	}
+	if ctx := ParseNaturalLanguage([]string{"pods"}); ctx.SessionTarget() != nil {
+		t.Errorf("expected no target for a listing, got %+v", ctx.SessionTarget())
+	}
+}
@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+)
+
+// Names are compared by trigrams of 6-bit character codes
+const (
+	gramSize  = 3
+	gramBits  = 6
+	gramCount = 1 << (gramSize * gramBits)
+)
+
+// gramCodes maps each byte of a name to 6 bits, ignoring case. Rare characters
+// share codes, which only lets a few more names through to the exact edit
+// distance check.
+var gramCodes = func() [256]uint8 {
+	var codes [256]uint8
+	for c := 1; c < 256; c++ {
+		switch {
+		case c >= 'a' && c <= 'z':
+			codes[c] = uint8(c-'a') + 1
+		case c >= 'A' && c <= 'Z':
+			codes[c] = uint8(c-'A') + 1
+		case c >= '0' && c <= '9':
+			codes[c] = uint8(c-'0') + 27
+		case c == '-':
+			codes[c] = 37
+		case c == '.':
+			codes[c] = 38
+		case c == '_':
+			codes[c] = 39
+		default:
+			codes[c] = 40 + uint8(c%24)
+		}
+	}
+	return codes
+}()
+
+// gramSet holds the trigrams of a name, one bit each
+type gramSet [gramCount / 64]uint64
+
+// indexEntry is one learned "namespace/name" entry
+type indexEntry struct {
+	name      string
+	namespace string
+	lower     string
+}
+
+// nameIndex looks up the learned "namespace/name" entries of one kind. It only
+// records where each entry splits: a skube run makes a handful of lookups, and
+// scanning 50k names with cheap filters takes less time than building a search
+// structure would. Fuzzy lookups only compute the edit distance of names of a
+// close length that share enough trigrams with the input. Results keep the
+// learned order of the entries, and entries without a namespace are skipped.
+type nameIndex struct {
+	entries []string
+	slashes []int32 // position of the "/" in each entry, or -1
+}
+
+// newNameIndex indexes "namespace/name" entries
+func newNameIndex(entries []string) *nameIndex {
+	idx := &nameIndex{entries: entries, slashes: make([]int32, len(entries))}
+	for i, entry := range entries {
+		idx.slashes[i] = int32(strings.IndexByte(entry, '/'))
+	}
+	return idx
+}
+
+// entry returns the namespace and name of entry i; ok is false for an entry
+// without a namespace, or one outside namespace when it is set
+func (idx *nameIndex) entry(i int, namespace string) (ns string, name string, ok bool) {
+	slash := idx.slashes[i]
+	if slash < 0 {
+		return "", "", false
+	}
+	ns, name = idx.entries[i][:slash], idx.entries[i][slash+1:]
+	return ns, name, namespace == "" || (len(ns) == len(namespace) && strings.EqualFold(ns, namespace))
+}
+
+// forEachGram calls fn with each trigram of s padded at both ends, so that
+// short names and their first and last letters are compared too. Trigrams
+// may repeat.
+func forEachGram(s string, fn func(g uint32)) {
+	var g uint32
+	for i := 0; i < len(s)+gramSize-1; i++ {
+		var c byte
+		if i < len(s) {
+			c = s[i]
+		}
+		g = (g<<gramBits | uint32(gramCodes[c])) & (gramCount - 1)
+		fn(g)
+	}
+}
+
+// uniqueGrams returns the distinct trigrams of s, as forEachGram packs them
+func uniqueGrams(s string) []uint32 {
+	var grams []uint32
+	forEachGram(s, func(g uint32) {
+		if !slices.Contains(grams, g) {
+			grams = append(grams, g)
+		}
+	})
+	return grams
+}
+
+// sharesGrams reports whether at least need of the trigrams of s, repeats
+// included, are in set. It inlines forEachGram and stops as soon as the answer
+// is known, since fuzzy lookups call it for every entry.
+func sharesGrams(s string, set *gramSet, need int) bool {
+	misses := len(s) + gramSize - 1 - need
+	var g uint32
+	for i := 0; i < len(s)+gramSize-1; i++ {
+		var c byte
+		if i < len(s) {
+			c = s[i]
+		}
+		g = (g<<gramBits | uint32(gramCodes[c])) & (gramCount - 1)
+		if set[g/64]>>(g%64)&1 != 0 {
+			if need--; need <= 0 {
+				return true
+			}
+		} else if misses--; misses < 0 {
+			return false
+		}
+	}
+	return need <= 0
+}
+
+// exact returns the entries called name, ignoring case
+func (idx *nameIndex) exact(name string, namespace string) []Candidate {
+	var matches []Candidate
+	for i := range idx.entries {
+		ns, entryName, ok := idx.entry(i, namespace)
+		if ok && len(entryName) == len(name) && strings.EqualFold(entryName, name) {
+			matches = append(matches, Candidate{Name: entryName, Namespace: ns})
+		}
+	}
+	return matches
+}
+
+// closest returns the entries closest to name within the fuzzy threshold.
+// Several entries are returned when they tie for the best distance.
+func (idx *nameIndex) closest(name string, namespace string) []Candidate {
+	threshold := calculateThreshold(name)
+	lower := strings.ToLower(name)
+	grams := uniqueGrams(lower)
+
+	// Every edit changes at most gramSize trigrams, so a name within the threshold
+	// shares at least this many of the input's trigrams
+	need := len(grams) - gramSize*threshold
+	var inInput gramSet
+	for _, g := range grams {
+		inInput[g/64] |= 1 << (g % 64)
+	}
+
+	best := -1
+	var matches []Candidate
+	for i := range idx.entries {
+		ns, entryName, ok := idx.entry(i, namespace)
+		if !ok {
+			continue
+		}
+		if diff := len(entryName) - len(lower); diff > threshold || -diff > threshold {
+			continue
+		}
+		if need > 0 && !sharesGrams(entryName, &inInput, need) {
+			continue
+		}
+		distance := LevenshteinDistance(lower, entryName)
+		if distance > threshold || (best >= 0 && distance > best) {
+			continue
+		}
+		if distance < best || best < 0 {
+			best, matches = distance, nil
+		}
+		matches = append(matches, Candidate{Name: entryName, Namespace: ns, Distance: distance})
+	}
+	return matches
+}
+
+// segmentMatches returns the entries that contain name as whole hyphen-separated
+// segments, e.g. "api" in "api-gateway" or "edge-api"
+func (idx *nameIndex) segmentMatches(name string, namespace string) []Candidate {
+	var matches []Candidate
+	for i := range idx.entries {
+		ns, entryName, ok := idx.entry(i, namespace)
+		if ok && hasSegments(entryName, name) {
+			matches = append(matches, Candidate{Name: entryName, Namespace: ns})
+		}
+	}
+	return matches
+}
+
+// hasSegments reports whether name is longer than word and contains it as whole
+// hyphen-separated segments, ignoring case
+func hasSegments(name string, word string) bool {
+	if len(name) <= len(word) {
+		return false
+	}
+	for start := 0; start+len(word) <= len(name); {
+		end := start + len(word)
+		if (end == len(name) || name[end] == '-') && strings.EqualFold(name[start:end], word) {
+			return true
+		}
+		next := strings.IndexByte(name[start:], '-')
+		if next < 0 {
+			return false
+		}
+		start += next + 1
+	}
+	return false
+}
+
+// startingWith returns the entries whose name starts with the lowercase letter
+// c, ignoring case, in learned order
+func (idx *nameIndex) startingWith(c byte, namespace string) []indexEntry {
+	var entries []indexEntry
+	for i := range idx.entries {
+		ns, entryName, ok := idx.entry(i, namespace)
+		if !ok || entryName == "" {
+			continue
+		}
+		if first := entryName[0]; first != c && !(first >= 'A' && first <= 'Z' && first+'a'-'A' == c) {
+			continue
+		}
+		entries = append(entries, indexEntry{name: entryName, namespace: ns, lower: strings.ToLower(entryName)})
+	}
+	return entries
+}
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+var benchWords = []string{
+	"api", "billing", "payment", "gateway", "worker", "auth", "search", "cache",
+	"notification", "inventory", "order", "user", "report", "media", "ledger", "sync",
+}
+
+// syntheticEntries returns n "namespace/name" entries spread over 200 namespaces
+func syntheticEntries(n int) []string {
+	entries := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		ns := fmt.Sprintf("team-%03d", i%200)
+		name := fmt.Sprintf("%s-%s-%d", benchWords[i%len(benchWords)], benchWords[(i/len(benchWords))%len(benchWords)], i)
+		entries = append(entries, ns+"/"+name)
+	}
+	return entries
+}
+
+// scanClosest is the exhaustive search the index replaces
+func scanClosest(entries []string, name string, namespace string) []Candidate {
+	threshold := calculateThreshold(name)
+	var matches []Candidate
+	for _, entry := range entries {
+		ns, entryName, _ := strings.Cut(entry, "/")
+		if namespace != "" && !strings.EqualFold(ns, namespace) {
+			continue
+		}
+		distance := LevenshteinDistance(name, entryName)
+		if distance > threshold {
+			continue
+		}
+		if len(matches) > 0 && distance < matches[0].Distance {
+			matches = nil
+		}
+		if len(matches) == 0 || distance == matches[0].Distance {
+			matches = append(matches, Candidate{Name: entryName, Namespace: ns, Distance: distance})
+		}
+	}
+	return matches
+}
+
+func TestNameIndexMatchesScan(t *testing.T) {
+	entries := append(syntheticEntries(5000), "prod/api", "staging/api", "prod/web-1", "prod/web-2", "bad-entry")
+	idx := newNameIndex(entries)
+
+	queries := []struct {
+		name      string
+		namespace string
+	}{
+		{"api", ""},
+		{"apj", "prod"},
+		{"web-3", "prod"},
+		{"billing-payment-17", ""},
+		{"bilIing-payment-17", ""},
+		{"billing-payment-17", "team-017"},
+		{"billing-payment-17", "team-018"},
+		{"gateway-auth-1234", ""},
+		{"gatewya-auth-1234", ""},
+		{"zz", ""},
+		{"checkout", "unknown"},
+	}
+
+	for _, q := range queries {
+		t.Run(q.name+"@"+q.namespace, func(t *testing.T) {
+			got := idx.closest(q.name, q.namespace)
+			want := scanClosest(entries, q.name, q.namespace)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("closest(%q, %q) = %+v, want %+v", q.name, q.namespace, got, want)
+			}
+		})
+	}
+}
+
+func TestNameIndexLookups(t *testing.T) {
+	idx := newNameIndex([]string{"prod/api-gateway", "prod/edge-api", "staging/API", "prod/apis", "dev/api-gw-v2"})
+
+	if got := idx.findExact("api", ""); got != "API" {
+		t.Errorf("expected a case-insensitive exact match, got %q", got)
+	}
+	if got := idx.findExact("api", "prod"); got != "" {
+		t.Errorf("expected no exact match in prod, got %q", got)
+	}
+
+	var names []string
+	for _, c := range idx.segmentMatches("api", "") {
+		names = append(names, c.Namespace+"/"+c.Name)
+	}
+	if expected := []string{"prod/api-gateway", "prod/edge-api", "dev/api-gw-v2"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected segment matches %v, got %v", expected, names)
+	}
+	if matches := idx.segmentMatches("api-gw", "dev"); len(matches) != 1 || matches[0].Name != "api-gw-v2" {
+		t.Errorf("expected multi-segment match api-gw-v2, got %+v", matches)
+	}
+}
+
+// benchPatterns returns patterns with n deployments, services and pods
+func benchPatterns(n int) *config.ClusterPatterns {
+	entries := syntheticEntries(n)
+	pods := make([]string, len(entries))
+	for i, entry := range entries {
+		pods[i] = entry + "-7d9f8b6c5-x2x4z"
+	}
+	return &config.ClusterPatterns{
+		Deployments: entries,
+		Services:    entries,
+		Pods:        pods,
+	}
+}
+
+// resolveBudget is how long resolving a name among 50k learned names may take in
+// a new resolver, which is what a single skube run waits for
+const resolveBudget = 10 * time.Millisecond
+
+func TestResolveWithinBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+	patterns := benchPatterns(50000)
+
+	tests := []struct {
+		name    string
+		resolve func(r *ResourceResolver) Resolution
+		want    string
+		method  string
+	}{
+		{
+			name:    "exact",
+			resolve: func(r *ResourceResolver) Resolution { return r.resolveAppName("payment-media-1234", "") },
+			want:    "payment-media-1234",
+			method:  MatchExact,
+		},
+		{
+			name:    "typo",
+			resolve: func(r *ResourceResolver) Resolution { return r.resolveAppName("paymnet-media-1234", "") },
+			want:    "payment-media-1234",
+			method:  MatchFuzzy,
+		},
+		{
+			name:    "typo in namespace",
+			resolve: func(r *ResourceResolver) Resolution { return r.resolveAppName("paymnet-media-1234", "team-034") },
+			want:    "payment-media-1234",
+			method:  MatchFuzzy,
+		},
+		{
+			name:    "unknown",
+			resolve: func(r *ResourceResolver) Resolution { return r.resolveAppName("checkout-service", "") },
+			want:    "checkout-service",
+			method:  MatchFallback,
+		},
+		{
+			name: "stale pod",
+			resolve: func(r *ResourceResolver) Resolution {
+				return r.resolvePodName("payment-media-1234-7d9f8b6c5-x2x4y", "")
+			},
+			want:   "payment-media-1234-7d9f8b6c5-x2x4z",
+			method: MatchFuzzy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep the fastest of a few runs, so that a busy machine does not fail the test
+			var fastest time.Duration
+			for i := 0; i < 5; i++ {
+				r := &ResourceResolver{patterns: patterns}
+				start := time.Now()
+				res := tt.resolve(r)
+				if elapsed := time.Since(start); i == 0 || elapsed < fastest {
+					fastest = elapsed
+				}
+				if res.Output != tt.want || res.Method != tt.method {
+					t.Fatalf("resolved to %q by %s, want %q by %s", res.Output, res.Method, tt.want, tt.method)
+				}
+			}
+			if fastest > resolveBudget {
+				t.Errorf("resolving took %v, want under %v", fastest, resolveBudget)
+			}
+		})
+	}
+}
+
+// BenchmarkResolveAppName50k resolves names against 50k deployments, each with a
+// new resolver as a skube run does: an exact name, a typo and a name that
+// matches nothing
+func BenchmarkResolveAppName50k(b *testing.B) {
+	patterns := benchPatterns(50000)
+	inputs := []string{"payment-media-1234", "paymnet-media-1234", "checkout-service"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		(&ResourceResolver{patterns: patterns}).resolveAppName(inputs[i%len(inputs)], "")
+	}
+}
+
+// BenchmarkResolveAppNameInNamespace50k resolves a typo within one namespace
+func BenchmarkResolveAppNameInNamespace50k(b *testing.B) {
+	patterns := benchPatterns(50000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		(&ResourceResolver{patterns: patterns}).resolveAppName("paymnet-media-1234", "team-034")
+	}
+}
+
+// BenchmarkResolvePodName50k resolves a stale pod name against 50k pods
+func BenchmarkResolvePodName50k(b *testing.B) {
+	patterns := benchPatterns(50000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		(&ResourceResolver{patterns: patterns}).resolvePodName("payment-media-1234-7d9f8b6c5-x2x4y", "")
+	}
+}
@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/geminal/skube/internal/config"
 )
 
 var (
@@ -28,12 +31,17 @@ const (
 	PrepInto = "into"
 
 	// Keywords/Resources
-	KwApp        = "app"
-	KwPod        = "pod"
-	KwDeployment = "deployment"
-	KwService    = "service"
-	KwNamespace  = "namespace"
-	KwFile       = "file"
+	KwApp         = "app"
+	KwPod         = "pod"
+	KwDeployment  = "deployment"
+	KwStatefulSet = "statefulset"
+	KwDaemonSet   = "daemonset"
+	KwJob         = "job"
+	KwCronJob     = "cronjob"
+	KwService     = "service"
+	KwNamespace   = "namespace"
+	KwFile        = "file"
+	KwContainer   = "container"
 
 	// Commands
 	CmdLogs     = "logs"
@@ -45,118 +53,287 @@ const (
 	CmdCopy     = "copy"
 	CmdApply    = "apply"
 	CmdGet      = "get"
+
+	CmdRolloutStatus = "rollout-status"
+	CmdResources     = "resources" // lists a resource type learned from API discovery
+
+	// Relative scaling operations
+	ScaleUp     = "up"
+	ScaleDown   = "down"
+	ScaleDouble = "double"
+	ScaleHalf   = "half"
+	ScaleBack   = "back"
 )
 
 type Context struct {
-	Command        string
-	Namespace      string
-	AppName        string
-	PodName        string
-	ServiceName    string
-	DeploymentName string
-	ResourceType   string
-	ResourceName   string
-	Port           string
-	Replicas       string
-	Follow         bool
-	Prefix         bool
-	DryRun         bool
-	SearchTerm     string
-	TailLines      int
-	MaxLogRequests int
-	FilePath       string
-	SourcePath     string
-	DestPath       string
+	Command         string
+	Namespace       string
+	AllNamespaces   bool
+	AppName         string
+	PodName         string
+	PodOwner        string // workload owning PodName as "kind/name", e.g. "deployment/api", when skube init learned it
+	PodPick         string // pick one pod of the app: PickNewest, PickOldest or PickReady
+	PodIndex        int    // pick the Nth pod of the app, in name order
+	Node            string // pick a pod running on this node
+	ServiceName     string
+	DeploymentName  string
+	WorkloadKind    string // kind of DeploymentName when it is not a deployment, e.g. KwStatefulSet
+	ResourceType    string
+	ResourceName    string
+	Container       string
+	AllContainers   bool
+	Selector        string
+	PodStatus       string
+	MinRestarts     int
+	Port            string
+	Replicas        string
+	ScaleOp         string // relative scaling; Replicas is the amount for ScaleUp and ScaleDown
+	Follow          bool
+	Prefix          bool
+	DryRun          bool
+	SearchTerm      string
+	SearchRegex     bool // SearchTerm is an extended regular expression
+	IgnoreCase      bool
+	TailLines       int
+	Since           string
+	SinceTime       string
+	MaxLogRequests  int
+	Previous        bool
+	EventType       string   // "Warning" or "Normal"
+	Exclusions      []string // namespaces for listings, text for logs, event types or namespaces for events
+	ParseError      error    // set when the input could not be resolved safely
+	Notes           []string // substitutions from conversation memory, shown before running
+	ContinueOnError bool     // keep running later steps when this step fails
+	FilePath        string
+	SourcePath      string
+	DestPath        string
 }
 
 func ParseNaturalLanguage(args []string) *Context {
+	return parseNaturalLanguage(args, nil)
+}
+
+// parseNaturalLanguage parses args, recording each decision in trace when it is not nil
+func parseNaturalLanguage(args []string, trace *ParseTrace) *Context {
+	// Split into tokens, keeping quoted phrases together
+	return parseTokens(tokenize(args), getMemory(), "", trace)
+}
+
+// parseTokens parses tokens that tokenize already split. References such as "it"
+// resolve to mem, the target of the last command; parsing never changes it.
+// namespace, when set, is used if args name none, before names are resolved.
+func parseTokens(tokens []token, mem *config.SessionTarget, namespace string, trace *ParseTrace) *Context {
 	ctx := &Context{}
 
-	// Handle single string input (e.g. from --ai flag fallback)
-	if len(args) == 1 && strings.Contains(args[0], " ") {
-		args = strings.Fields(args[0])
-	}
+	// Team-specific verbs, resource words and stop words from the config
+	vocab := getVocabulary()
+	tokens = rewriteVocabulary(tokens, vocab, trace)
+
+	// "it", "that app" and "same namespace" refer to the last command's target
+	args, usedTarget := rewriteReferences(tokens, mem, ctx, trace)
 
 	input := strings.Join(args, " ")
 
 	// Early namespace detection (namespace-first syntax)
 	// Supports: "skube in production logs from app myapp"
-	if len(args) > 1 && strings.ToLower(args[0]) == PrepIn {
+	if consumed := allNamespacesPhrase(args, 0); consumed > 0 {
+		// "in all namespaces get pods"
+		before := *ctx
+		ctx.AllNamespaces = true
+		trace.record(args[:consumed], StageNamespaceFirst, before, ctx)
+		args = args[consumed:]
+		input = strings.Join(args, " ")
+	} else if len(args) > 1 && strings.ToLower(args[0]) == PrepIn {
+		before := *ctx
 		ctx.Namespace = args[1]
+		trace.record(args[:2], StageNamespaceFirst, before, ctx)
 		args = args[2:] // Remove "in <namespace>" from args
 		input = strings.Join(args, " ")
 	}
 
 	for i := 0; i < len(args); i++ {
 		word := strings.ToLower(args[i])
-
-		// Skip stop words
-		if stopWords[word] {
-			continue
+		start := i
+		before := *ctx
+
+		var stage string
+		switch {
+		case stopWords[word]:
+			// Skip stop words
+			stage = StageStopWord
+		case parseAllNamespaces(args, &i, ctx):
+			// Before commands, so "all namespaces" does not list namespaces
+			stage = StageFlags
+		case parseCommand(word, args, &i, ctx):
+			stage = StageCommand
+		case parseResource(word, args, &i, ctx):
+			stage = StageResource
+		case parseFlags(word, args, &i, ctx):
+			// Flags and modifiers
+			stage = StageFlags
+		case parsePrepositions(word, args, &i, ctx):
+			// Prepositions and context
+			stage = StagePrepositions
+		default:
+			// Default fallback logic
+			parseDefault(word, input, ctx)
+			stage = StageDefault
 		}
 
-		// Try to parse as a command
-		if parseCommand(word, args, &i, ctx) {
-			continue
-		}
+		trace.record(args[start:i+1], stage, before, ctx)
+	}
 
-		// Try to parse as a resource type
-		if parseResource(word, args, &i, ctx) {
-			continue
-		}
+	// "find api in any namespace": a search without a command looks for resources by name
+	if ctx.Command == "" && ctx.SearchTerm != "" {
+		ctx.Command = "all"
+	}
 
-		// Try to parse flags and modifiers
-		if parseFlags(word, args, &i, ctx) {
-			continue
-		}
+	// "shell into the newest api pod": the name is the app to pick a pod from
+	if ctx.HasPodPick() && ctx.AppName == "" && ctx.PodName != "" {
+		ctx.AppName = ctx.PodName
+		ctx.PodName = ""
+	}
 
-		// Try to parse prepositions and context
-		if parsePrepositions(word, args, &i, ctx) {
-			continue
-		}
+	// "restart it" acts in the namespace of the remembered target
+	if usedTarget && ctx.Namespace == "" && !ctx.AllNamespaces && mem.Namespace != "" {
+		ctx.Namespace = mem.Namespace
+		ctx.Notes = append(ctx.Notes, "namespace "+ctx.Namespace+" from the last command")
+	}
 
-		// Default fallback logic
-		parseDefault(word, input, ctx)
+	// A namespace carried over from an earlier step narrows name resolution
+	if ctx.Namespace == "" && !ctx.AllNamespaces && namespace != "" {
+		ctx.Namespace = namespace
 	}
 
-	// Post-processing: resolve resource names using cluster patterns
-	resolveResourceNames(ctx)
+	// Post-processing: expand nicknames, then resolve resource names using cluster patterns
+	applyNicknames(ctx, vocab, trace)
+	resolveResourceNames(ctx, trace)
 
 	return ctx
 }
 
 // resolveResourceNames uses the resource resolver to improve name matching
-func resolveResourceNames(ctx *Context) {
+func resolveResourceNames(ctx *Context, trace *ParseTrace) {
 	resolver := getResolver()
 
 	// Only resolve if we have learned patterns
 	if !resolver.HasPatterns() {
 		return
 	}
+	if trace != nil {
+		trace.PatternsLoaded = true
+	}
 
 	// Resolve namespace with fuzzy matching
 	if ctx.Namespace != "" {
-		ctx.Namespace = resolver.ResolveNamespace(ctx.Namespace)
+		ctx.Namespace = trace.resolved("namespace", resolver.resolveNamespace(ctx.Namespace))
 	}
 
 	// Resolve app name with fuzzy matching and cluster awareness
 	if ctx.AppName != "" {
-		ctx.AppName = resolver.ResolveAppName(ctx.AppName, ctx.Namespace)
+		ctx.AppName = applyResolution(ctx, trace, "app", resolver.resolveAppName(ctx.AppName, ctx.Namespace))
 	}
 
+	// Pick the workload kind first, so that only deployments resolve as deployments
+	resolveWorkload(ctx, resolver, trace)
+
 	// Resolve deployment name
-	if ctx.DeploymentName != "" {
-		ctx.DeploymentName = resolver.ResolveAppName(ctx.DeploymentName, ctx.Namespace)
+	if ctx.DeploymentName != "" && ctx.WorkloadKind == "" {
+		ctx.DeploymentName = applyResolution(ctx, trace, "deployment", resolver.resolveAppName(ctx.DeploymentName, ctx.Namespace))
 	}
 
 	// Resolve service name
 	if ctx.ServiceName != "" {
-		ctx.ServiceName = resolver.ResolveServiceName(ctx.ServiceName, ctx.Namespace)
+		ctx.ServiceName = applyResolution(ctx, trace, "service", resolver.resolveServiceName(ctx.ServiceName, ctx.Namespace))
 	}
 
 	// Resolve pod name
 	if ctx.PodName != "" {
-		ctx.PodName = resolver.ResolvePodName(ctx.PodName, ctx.Namespace)
+		ctx.PodName = trace.resolved("pod", resolver.resolvePodName(ctx.PodName, ctx.Namespace))
+	}
+
+	inferNamespace(ctx, resolver)
+	resolvePodOwner(ctx, resolver)
+
+	// Resolve container name against the containers learned for the target
+	if ctx.Container != "" {
+		ctx.Container = trace.resolved("container", resolver.resolveContainerName(ctx.Container, ctx.Namespace, ctx.AppName, ctx.PodName))
+	}
+}
+
+// resolveWorkload resolves the name of a statefulset, daemonset, job or cronjob. An explicit
+// kind is kept; a deployment name, or a pod name to restart or read logs from that is no pod,
+// switches to the kind of the workload it matches.
+func resolveWorkload(ctx *Context, resolver *ResourceResolver, trace *ParseTrace) {
+	if ctx.WorkloadKind != "" {
+		if ctx.DeploymentName != "" {
+			ctx.DeploymentName = applyResolution(ctx, trace, ctx.WorkloadKind, resolver.resolveWorkloadName(ctx.WorkloadKind, ctx.DeploymentName, ctx.Namespace))
+		}
+		return
+	}
+
+	name := ctx.DeploymentName
+	if name == "" && (ctx.Command == CmdRestart || ctx.Command == CmdLogs) && resolver.findExactPodMatch(ctx.PodName, ctx.Namespace) == "" {
+		name = ctx.PodName
+	}
+	if name == "" {
+		return
+	}
+
+	kind, res, ok := resolver.resolveWorkloadKind(name, ctx.Namespace)
+	if !ok {
+		return
+	}
+	ctx.WorkloadKind = kind
+	ctx.DeploymentName = applyResolution(ctx, trace, kind, res)
+	ctx.PodName = ""
+}
+
+// inferNamespace fills in the namespace when none was given and the target
+// exists in exactly one namespace, noting it so the user sees where skube acts
+func inferNamespace(ctx *Context, resolver *ResourceResolver) {
+	if ctx.Namespace != "" || ctx.AllNamespaces {
+		return
+	}
+
+	kind, name := "", ""
+	switch {
+	case ctx.DeploymentName != "":
+		kind, name = KwDeployment, ctx.DeploymentName
+		if ctx.WorkloadKind != "" {
+			kind = ctx.WorkloadKind
+		}
+	case ctx.AppName != "":
+		kind, name = KwApp, ctx.AppName
+	case ctx.ServiceName != "":
+		kind, name = KwService, ctx.ServiceName
+	case ctx.PodName != "":
+		kind, name = KwPod, ctx.PodName
+	default:
+		return
+	}
+
+	if ns := resolver.InferNamespace(kind, name); ns != "" {
+		ctx.Namespace = ns
+		ctx.Notes = append(ctx.Notes, fmt.Sprintf("namespace %s: the only namespace with %s %s", ns, kind, name))
+	}
+}
+
+// resolvePodOwner records the workload owning the pod, so that a pod replaced since
+// skube init, or a partial pod name, can be swapped for a running pod of it
+func resolvePodOwner(ctx *Context, resolver *ResourceResolver) {
+	if ctx.PodName == "" || ctx.HasPodPick() {
+		return
+	}
+	owner, ns := resolver.podOwner(ctx.PodName, ctx.Namespace)
+	if owner == "" {
+		return
+	}
+	ctx.PodOwner = owner
+	if ctx.Namespace == "" && !ctx.AllNamespaces {
+		ctx.Namespace = ns
+		kind, name, _ := strings.Cut(owner, "/")
+		ctx.Notes = append(ctx.Notes, fmt.Sprintf("namespace %s: where %s %s owns pod %s", ns, kind, name, ctx.PodName))
 	}
 }
 
@@ -191,12 +368,17 @@ var stopWords = map[string]bool{
 	"for": true, "target": true,
 	"resource": true, "resources": true, "object": true, "objects": true,
 	"here": true, "now": true,
+	"is": true, "are": true,
 }
 
 var resourceAliases = map[string]string{
 	"namespaces": "namespace", "ns": "namespace", "namespace": "namespace",
 	"pods": "pod", "pod": "pod",
 	"deployments": "deployment", "deploy": "deployment", "deployment": "deployment",
+	"statefulsets": "statefulset", "statefulset": "statefulset", "sts": "statefulset",
+	"daemonsets": "daemonset", "daemonset": "daemonset", "ds": "daemonset",
+	"jobs": "job", "job": "job",
+	"cronjobs": "cronjob", "cronjob": "cronjob", "cj": "cronjob",
 	"services": "service", "svc": "service", "service": "service",
 	"nodes": "node", "no": "node",
 	"configmaps": "configmap", "cm": "configmap",
@@ -209,6 +391,10 @@ var getCommandMap = map[string]string{
 	"namespaces": "namespaces", "ns": "namespaces",
 	"pods": "pods", "pod": "pods",
 	"deployments": "deployments", "deploy": "deployments",
+	"statefulsets": "statefulsets", "sts": "statefulsets",
+	"daemonsets": "daemonsets", "ds": "daemonsets",
+	"jobs": "jobs",
+	"cronjobs": "cronjobs", "cj": "cronjobs",
 	"services": "services", "svc": "services",
 	"nodes": "nodes", "no": "nodes",
 	"configmaps": "configmaps", "cm": "configmaps",
@@ -232,6 +418,7 @@ func parseCommand(word string, args []string, index *int, ctx *Context) bool {
 			if nextWord == "last" && i+2 < len(args) {
 				if lines, err := strconv.Atoi(args[i+2]); err == nil {
 					ctx.TailLines = lines
+					*index += 2
 				}
 			}
 		}
@@ -242,7 +429,7 @@ func parseCommand(word string, args []string, index *int, ctx *Context) bool {
 	if word == "check" {
 		if i+1 < len(args) && args[i+1] == "usage" {
 			ctx.Command = "metrics"
-			if i+2 < len(args) {
+			if i+2 < len(args) && allNamespacesPhrase(args, i+2) == 0 {
 				ctx.ResourceType = args[i+2]
 				*index += 2
 			}
@@ -284,6 +471,13 @@ func parseCommand(word string, args []string, index *int, ctx *Context) bool {
 		// If not a special show command, fall through to alias lookup (show -> get)
 	}
 
+	// Special case for "rollout status"
+	if word == "rollout" && i+1 < len(args) && strings.ToLower(args[i+1]) == "status" {
+		ctx.Command = CmdRolloutStatus
+		*index++
+		return true
+	}
+
 	// Special case for "list contexts"
 	if word == "list" && i+1 < len(args) && (args[i+1] == "contexts" || args[i+1] == "context") {
 		ctx.Command = "config"
@@ -292,6 +486,11 @@ func parseCommand(word string, args []string, index *int, ctx *Context) bool {
 		return true
 	}
 
+	// "describe rollout api" names a learned resource type, not the rollout command
+	if _, ok := learnedResourceType(word, ctx); ok && isResourceCommand(ctx.Command) {
+		return false
+	}
+
 	// Lookup in alias map
 	if cmd, ok := commandAliases[word]; ok {
 		ctx.Command = cmd
@@ -366,8 +565,9 @@ func parseResource(word string, args []string, index *int, ctx *Context) bool {
 
 		// Type Correction: If we see "deployment" but have a PodName (likely from parseDefault),
 		// and no DeploymentName, assume the PodName was actually the DeploymentName.
-		if resType == KwDeployment && ctx.PodName != "" && ctx.DeploymentName == "" {
+		if isWorkload(resType) && ctx.PodName != "" && ctx.DeploymentName == "" {
 			ctx.DeploymentName = ctx.PodName
+			ctx.WorkloadKind = workloadKindOf(resType)
 			ctx.PodName = ""
 			// Don't return true yet, we might still want to consume next word if it's a name?
 			// But usually "backend deployment" -> backend is the name.
@@ -377,19 +577,20 @@ func parseResource(word string, args []string, index *int, ctx *Context) bool {
 			return true
 		}
 
-		// Context setting (e.g. "deployment api")
-		if resType == KwDeployment && i+1 < len(args) && ctx.DeploymentName == "" {
+		// Context setting (e.g. "deployment api", "sts db")
+		if isWorkload(resType) && i+1 < len(args) && ctx.DeploymentName == "" {
 			// Check if next word is a stop word or preposition, if so, don't consume it
 			nextWord := strings.ToLower(args[i+1])
-			if !stopWords[nextWord] && nextWord != PrepIn && nextWord != PrepFrom && nextWord != PrepTo {
+			if !stopWords[nextWord] && !endsName(nextWord, ctx) {
 				ctx.DeploymentName = args[i+1]
+				ctx.WorkloadKind = workloadKindOf(resType)
 				*index++
 			}
 			return true
 		}
 		if resType == KwService && i+1 < len(args) && ctx.ServiceName == "" {
 			nextWord := strings.ToLower(args[i+1])
-			if !stopWords[nextWord] && nextWord != PrepIn && nextWord != PrepFrom && nextWord != PrepTo {
+			if !stopWords[nextWord] && !endsName(nextWord, ctx) {
 				ctx.ServiceName = args[i+1]
 				*index++
 			}
@@ -397,7 +598,7 @@ func parseResource(word string, args []string, index *int, ctx *Context) bool {
 		}
 		if resType == KwNamespace && i+1 < len(args) && ctx.Namespace == "" {
 			nextWord := strings.ToLower(args[i+1])
-			if !stopWords[nextWord] && nextWord != PrepIn && nextWord != PrepFrom && nextWord != PrepTo {
+			if !stopWords[nextWord] && !endsName(nextWord, ctx) {
 				ctx.Namespace = args[i+1]
 				*index++
 			}
@@ -415,14 +616,60 @@ func parseResource(word string, args []string, index *int, ctx *Context) bool {
 		return true
 	}
 
+	// Resource types learned by API discovery, e.g. "rollouts", "certs" or "vs"
+	if resourceType, ok := learnedResourceType(word, ctx); ok {
+		if ctx.Command == "" || ctx.Command == CmdGet {
+			ctx.Command = CmdResources
+		}
+		ctx.ResourceType = resourceType
+		return true
+	}
+
 	return false
 }
 
+// learnedResourceType returns the group-qualified type of a resource type learned
+// by API discovery. Only listings and resource commands without a type take one,
+// so words like "role" or "lease" elsewhere in a sentence stay names.
+func learnedResourceType(word string, ctx *Context) (string, bool) {
+	if ctx.Command != "" && ctx.Command != CmdGet && !(isResourceCommand(ctx.Command) && ctx.ResourceType == "") {
+		return "", false
+	}
+	resource, ok := getResolver().patterns.FindAPIResource(word)
+	if !ok {
+		return "", false
+	}
+	return resource.FullName(), true
+}
+
+// workloadKinds lists the workload kinds besides deployments, in the order
+// names are matched against them
+var workloadKinds = []string{KwStatefulSet, KwDaemonSet, KwJob, KwCronJob}
+
+// isWorkload reports whether resType names a deployment or another workload kind
+func isWorkload(resType string) bool {
+	return resType == KwDeployment || workloadKindOf(resType) != ""
+}
+
+// workloadKindOf returns resType when it is a workload kind other than deployment, or ""
+func workloadKindOf(resType string) string {
+	for _, kind := range workloadKinds {
+		if resType == kind {
+			return kind
+		}
+	}
+	return ""
+}
+
 func isResourceCommand(cmd string) bool {
 	return cmd == "delete" || cmd == "edit" || cmd == "explain" || cmd == "describe"
 }
 
 func parseFlags(word string, args []string, index *int, ctx *Context) bool {
+	if parsePodPick(word, args, index, ctx) {
+		return true
+	}
+
 	i := *index
 	switch word {
 	case "--dry-run":
@@ -434,6 +681,10 @@ func parseFlags(word string, args []string, index *int, ctx *Context) bool {
 			if ctx.Command == CmdCopy {
 				ctx.DestPath = args[i+1]
 				*index++
+			} else if op := scaleFactorWord(args[i+1]); op != "" && ctx.Command == CmdScale {
+				// "scale worker to half"
+				ctx.ScaleOp = op
+				*index++
 			} else {
 				ctx.Replicas = args[i+1]
 				*index++
@@ -441,6 +692,22 @@ func parseFlags(word string, args []string, index *int, ctx *Context) bool {
 		}
 		return true
 
+	case "up", "down", "double", "half", "halve", "back":
+		// "scale api up by 2", "scale worker down to half", "double api", "scale api back"
+		if ctx.Command == "" && (word == "double" || word == "half" || word == "halve") {
+			ctx.Command = CmdScale
+		}
+		if ctx.Command != CmdScale {
+			return false
+		}
+		if op := scaleFactorWord(word); op != "" {
+			ctx.ScaleOp = op
+			return true
+		}
+		ctx.ScaleOp = word
+		*index += parseScaleAmount(args, i+1, ctx)
+		return true
+
 	case "port":
 		if i+1 < len(args) {
 			ctx.Port = args[i+1]
@@ -461,13 +728,51 @@ func parseFlags(word string, args []string, index *int, ctx *Context) bool {
 		}
 		return true
 
-	case "search", "find", "filter", "grep":
-		if i+1 < len(args) {
-			ctx.SearchTerm = strings.Trim(args[i+1], `"'`)
-			*index++
+	case "search", "find", "filter", "grep", "regex", "regexp":
+		// "search for timeout", "grep -i regex 'time(d )?out'", "find /5\d\d/"
+		j := i + 1
+		if word == "regex" || word == "regexp" {
+			ctx.SearchRegex = true
+		}
+		for j < len(args) {
+			next := strings.ToLower(args[j])
+			if next == "regex" || next == "regexp" || next == "pattern" {
+				ctx.SearchRegex = true
+			} else if next == "-i" || next == "--ignore-case" {
+				ctx.IgnoreCase = true
+			} else if next != "for" {
+				break
+			}
+			j++
+		}
+		if j < len(args) {
+			term := strings.Trim(args[j], `"'`)
+			if len(term) > 2 && strings.HasPrefix(term, "/") && strings.HasSuffix(term, "/") {
+				term = term[1 : len(term)-1]
+				ctx.SearchRegex = true
+			}
+			ctx.SearchTerm = term
+			*index = j
 		}
 		return true
 
+	case "ignoring", "ignore", "case", "case-insensitive", "-i", "--ignore-case":
+		// "ignoring case", "ignore case", "case insensitive"
+		if word == "case-insensitive" || word == "-i" || word == "--ignore-case" {
+			ctx.IgnoreCase = true
+			return true
+		}
+		if i+1 < len(args) {
+			next := strings.ToLower(args[i+1])
+			if ((word == "ignoring" || word == "ignore") && next == "case") ||
+				(word == "case" && (next == "insensitive" || next == "insensitively")) {
+				ctx.IgnoreCase = true
+				*index++
+				return true
+			}
+		}
+		return false
+
 	case "max":
 		// "max 30" or "max log requests 30" for --max-log-requests
 		if i+1 < len(args) {
@@ -484,6 +789,125 @@ func parseFlags(word string, args []string, index *int, ctx *Context) bool {
 		}
 		return true
 
+	case "label", "labels", "labeled", "labelled", "selector", "where", "-l", "--selector":
+		// "with label tier=backend", "where team is payments and tier is backend"
+		if terms, consumed := collectSelector(args, i+1); consumed > 0 {
+			if ctx.Selector != "" {
+				terms = append([]string{ctx.Selector}, terms...)
+			}
+			ctx.Selector = strings.Join(terms, ",")
+			*index += consumed
+		}
+		return true
+
+	case "crashing", "crashed", "crashlooping", "pending", "failed", "failing", "evicted", "unready", "not":
+		// "logs of the crashed api" wants the previous container instance
+		if ctx.Command == CmdLogs && previousWords[word] {
+			ctx.Previous = true
+			return true
+		}
+		// Pod status filters: "pods that are crashing", "not ready pods"
+		status := podStatusWords[word]
+		if word == "not" {
+			if i+1 >= len(args) || strings.ToLower(args[i+1]) != "ready" {
+				return false
+			}
+			*index++
+		}
+		ctx.PodStatus = status
+		usePodListing(ctx)
+		return true
+
+	case "except", "excluding", "exclude", "without", "but":
+		// "pods except kube-system", "logs without healthcheck", "events but not normal"
+		start := i + 1
+		if word == "but" {
+			if i+1 >= len(args) || strings.ToLower(args[i+1]) != "not" {
+				return false
+			}
+			start++
+		}
+		if terms, consumed := collectExclusions(args, start); consumed > 0 {
+			ctx.Exclusions = append(ctx.Exclusions, terms...)
+			*index = start + consumed - 1
+			return true
+		}
+		return false
+
+	case "warning", "warnings":
+		// "warning events in prod"
+		ctx.EventType = "Warning"
+		return true
+
+	case "previous", "--previous", "-p":
+		// "previous logs from api"
+		ctx.Previous = true
+		return true
+
+	case "before":
+		// "logs before restart", "logs before the crash"
+		if consumed := parseBeforeRestart(args, i+1); consumed > 0 {
+			ctx.Previous = true
+			*index += consumed
+			return true
+		}
+		return false
+
+	case "since":
+		// "since 15 minutes ago", "since 10:30"
+		if consumed := parseSince(args, i+1, ctx); consumed > 0 {
+			*index += consumed
+			return true
+		}
+		return false
+
+	case "last", "past":
+		// "in the last hour", "past 30 minutes"
+		if consumed := parseLast(args, i+1, ctx); consumed > 0 {
+			*index += consumed
+			return true
+		}
+		return false
+
+	case "restarted", "restarting":
+		// "restarted more than 3 times" or just "restarting"
+		ctx.MinRestarts = 1
+		if i+3 < len(args) && strings.ToLower(args[i+1]) == "more" && strings.ToLower(args[i+2]) == "than" {
+			if n, err := strconv.Atoi(args[i+3]); err == nil {
+				ctx.MinRestarts = n + 1
+				*index += 3
+				if i+4 < len(args) && strings.ToLower(args[i+4]) == "times" {
+					*index++
+				}
+			}
+		}
+		usePodListing(ctx)
+		return true
+
+	case KwContainer, "-c", "--container":
+		// "container worker", "shell into api container app"
+		if i+1 < len(args) {
+			nextWord := strings.ToLower(args[i+1])
+			if nextWord != PrepIn && nextWord != PrepFrom && nextWord != PrepOf && nextWord != PrepTo && nextWord != PrepInto {
+				ctx.Container = args[i+1]
+				*index++
+			}
+		}
+		return true
+
+	case "all", "every", "--all-containers":
+		// "all containers", "every container"
+		if word == "--all-containers" {
+			ctx.AllContainers = true
+			return true
+		}
+		if i+1 < len(args) && isContainersWord(args[i+1]) {
+			ctx.AllContainers = true
+			*index++
+			return true
+		}
+		return false
+
 	case "-n", "--namespace":
 		if i+1 < len(args) {
 			ctx.Namespace = args[i+1]
@@ -494,6 +918,150 @@ func parseFlags(word string, args []string, index *int, ctx *Context) bool {
 	return false
 }
 
+// parseAllNamespaces sets AllNamespaces for "everywhere", "across all namespaces",
+// "in any namespace" and similar phrases starting at the current word
+func parseAllNamespaces(args []string, index *int, ctx *Context) bool {
+	consumed := allNamespacesPhrase(args, *index)
+	if consumed == 0 {
+		return false
+	}
+	ctx.AllNamespaces = true
+	*index += consumed - 1
+	return true
+}
+
+// allNamespacesPhrase returns the number of words in an all-namespaces phrase at start, or 0
+func allNamespacesPhrase(args []string, start int) int {
+	word := func(offset int) string {
+		if start+offset < len(args) {
+			return strings.ToLower(args[start+offset])
+		}
+		return ""
+	}
+	isNamespaces := func(w string) bool {
+		return w == KwNamespace || w == "namespaces" || w == "ns"
+	}
+
+	switch word(0) {
+	case "everywhere", "cluster-wide", "clusterwide", "-a", "--all-namespaces":
+		return 1
+	case "all", "any", "every", "each":
+		// "all namespaces", "any namespace"
+		if isNamespaces(word(1)) {
+			return 2
+		}
+	case PrepIn, "across", PrepFrom:
+		// "across namespaces", "in all namespaces", "across the cluster"
+		if word(0) == "across" && isNamespaces(word(1)) {
+			return 2
+		}
+		quantifier := word(1)
+		if quantifier == "all" || quantifier == "any" || quantifier == "every" || quantifier == "each" {
+			if isNamespaces(word(2)) {
+				return 3
+			}
+		}
+		if word(0) == "across" && quantifier == "the" && word(2) == "cluster" {
+			return 3
+		}
+	}
+	return 0
+}
+
+// scaleFactorWord returns ScaleDouble or ScaleHalf for words that multiply the replica count
+func scaleFactorWord(word string) string {
+	switch strings.ToLower(word) {
+	case "double", "twice":
+		return ScaleDouble
+	case "half", "halve":
+		return ScaleHalf
+	}
+	return ""
+}
+
+// parseScaleAmount reads what follows "up", "down" or "back": "by 2", "2", "to half",
+// "to previous". Returns the number of words consumed.
+func parseScaleAmount(args []string, start int, ctx *Context) int {
+	i := start
+	if i < len(args) {
+		if next := strings.ToLower(args[i]); next == "by" || next == PrepTo {
+			i++
+		}
+	}
+	if i >= len(args) {
+		return 0
+	}
+
+	next := strings.ToLower(args[i])
+	switch {
+	case ctx.ScaleOp == ScaleBack:
+		// "back to previous", "back to before"
+		if next == "previous" || next == "before" || next == "last" {
+			return i + 1 - start
+		}
+	case scaleFactorWord(next) != "":
+		// "down to half"
+		ctx.ScaleOp = scaleFactorWord(next)
+		return i + 1 - start
+	default:
+		if _, err := strconv.Atoi(next); err == nil {
+			ctx.Replicas = next
+			return i + 1 - start
+		}
+	}
+	return 0
+}
+
+// isContainersWord reports whether word is "container" or "containers"
+func isContainersWord(word string) bool {
+	word = strings.ToLower(word)
+	return word == KwContainer || word == "containers"
+}
+
+// previousWords mark logs of the previous (crashed) container instance
+var previousWords = map[string]bool{
+	"crashed": true, "crashing": true, "crashlooping": true, "failed": true, "previous": true,
+}
+
+// restartWords end "before ..." phrases that ask for the previous container instance
+var restartWords = map[string]bool{
+	"restart": true, "restarted": true, "restarting": true, "restarts": true,
+	"crash": true, "crashed": true, "crashing": true,
+}
+
+// parseBeforeRestart matches "restart", "the restart", "it restarted" or "the last crash"
+// after "before". Returns the number of words consumed, or 0.
+func parseBeforeRestart(args []string, start int) int {
+	for i := start; i < len(args) && i < start+3; i++ {
+		word := strings.ToLower(args[i])
+		if restartWords[word] {
+			return i - start + 1
+		}
+		if word != "the" && word != "it" && word != "its" && word != "last" {
+			return 0
+		}
+	}
+	return 0
+}
+
+// podStatusWords maps status words to pod status filters
+var podStatusWords = map[string]string{
+	"crashing": "crashing", "crashed": "crashing", "crashlooping": "crashing",
+	"pending": "pending",
+	"failed":  "failed", "failing": "failed",
+	"evicted": "evicted",
+	"unready": "not-ready", "not": "not-ready",
+}
+
+// usePodListing turns a status-filtered query into a pod listing,
+// e.g. "what pods are crashing" parses "what" as explain and "pods" as its type
+func usePodListing(ctx *Context) {
+	if ctx.Command == "" || ctx.Command == CmdGet || (ctx.Command == "explain" && (ctx.ResourceType == "" || ctx.ResourceType == KwPod)) {
+		ctx.Command = "pods"
+		ctx.ResourceType = ""
+	}
+}
+
 func parsePrepositions(word string, args []string, index *int, ctx *Context) bool {
 	i := *index
 	switch word {
@@ -503,21 +1071,34 @@ func parsePrepositions(word string, args []string, index *int, ctx *Context) boo
 			if args[i+1] == KwApp {
 				if i+2 < len(args) {
 					// Collect multi-word app name
-					appName := collectResourceName(args, i+2)
+					appName := collectResourceName(args, i+2, ctx)
 					ctx.AppName = appName.name
 					*index += 1 + appName.wordCount
 				}
 			} else if args[i+1] == KwPod {
 				if i+2 < len(args) {
-					podName := collectResourceName(args, i+2)
+					podName := collectResourceName(args, i+2, ctx)
 					ctx.PodName = podName.name
 					*index += 1 + podName.wordCount
 				}
 			} else {
+				// Skip articles and crash qualifiers: "logs of the crashed api"
+				start := i + 1
+				for start < len(args) {
+					next := strings.ToLower(args[start])
+					if ctx.Command == CmdLogs && previousWords[next] {
+						ctx.Previous = true
+					} else if !stopWords[next] {
+						break
+					}
+					start++
+				}
 				// Collect multi-word resource name
-				resourceName := collectResourceName(args, i+1)
-				ctx.AppName = resourceName.name
-				*index += resourceName.wordCount
+				resourceName := collectResourceName(args, start, ctx)
+				if resourceName.wordCount > 0 {
+					ctx.AppName = resourceName.name
+					*index += start - (i + 1) + resourceName.wordCount
+				}
 			}
 		}
 		return true
@@ -525,24 +1106,39 @@ func parsePrepositions(word string, args []string, index *int, ctx *Context) boo
 	case PrepFrom, PrepIn, PrepInto:
 		if i+1 < len(args) {
 			nextWord := args[i+1]
-			if nextWord == KwPod && i+2 < len(args) {
-				podName := collectResourceName(args, i+2)
+			if nextWord == KwContainer && i+2 < len(args) {
+				// "logs from container worker of api"
+				ctx.Container = args[i+2]
+				*index += 2
+			} else if (nextWord == "all" || nextWord == "every") && i+2 < len(args) && isContainersWord(args[i+2]) {
+				// "logs of api from all containers"
+				ctx.AllContainers = true
+				*index += 2
+			} else if word == PrepInto && ctx.Command == CmdShell && nextWord != KwPod && ctx.PodName == "" {
+				// "shell into api" targets a pod, not a namespace
+				podName := collectResourceName(args, i+1, ctx)
+				ctx.PodName = podName.name
+				*index += podName.wordCount
+			} else if nextWord == KwPod && i+2 < len(args) {
+				podName := collectResourceName(args, i+2, ctx)
 				ctx.PodName = podName.name
 				*index += 1 + podName.wordCount
-			} else if nextWord == KwDeployment && i+2 < len(args) {
-				depName := collectResourceName(args, i+2)
+			} else if isWorkload(nextWord) && i+2 < len(args) {
+				// "logs from deployment api", "restart of sts db"
+				depName := collectResourceName(args, i+2, ctx)
 				ctx.DeploymentName = depName.name
+				ctx.WorkloadKind = workloadKindOf(nextWord)
 				*index += 1 + depName.wordCount
 			} else if nextWord == KwService && i+2 < len(args) {
-				svcName := collectResourceName(args, i+2)
+				svcName := collectResourceName(args, i+2, ctx)
 				ctx.ServiceName = svcName.name
 				*index += 1 + svcName.wordCount
 			} else if nextWord == KwNamespace && i+2 < len(args) {
-				nsName := collectResourceName(args, i+2)
+				nsName := collectResourceName(args, i+2, ctx)
 				ctx.Namespace = nsName.name
 				*index += 1 + nsName.wordCount
 			} else if nextWord == KwApp && i+2 < len(args) {
-				appName := collectResourceName(args, i+2)
+				appName := collectResourceName(args, i+2, ctx)
 				ctx.AppName = appName.name
 				*index += 1 + appName.wordCount
 			} else if nextWord == KwFile && i+2 < len(args) {
@@ -553,11 +1149,18 @@ func parsePrepositions(word string, args []string, index *int, ctx *Context) boo
 					ctx.SourcePath = args[i+2]
 				}
 				*index += 2
-			} else if nextWord != KwPod && nextWord != KwDeployment && nextWord != KwService && nextWord != KwFile && nextWord != KwApp {
-				// This is likely a namespace
-				nsName := collectResourceName(args, i+1)
-				ctx.Namespace = nsName.name
-				*index += nsName.wordCount
+			} else if word == PrepFrom && ctx.Command == CmdLogs && ctx.PodName == "" && ctx.AppName == "" && ctx.Selector == "" && !endsName(strings.ToLower(nextWord), ctx) {
+				// "previous logs from api": logs need a target, so the name is an app
+				appName := collectResourceName(args, i+1, ctx)
+				ctx.AppName = appName.name
+				*index += appName.wordCount
+			} else if nextWord != KwPod && !isWorkload(nextWord) && nextWord != KwService && nextWord != KwFile && nextWord != KwApp {
+				// This is likely a namespace ("in the last hour" has none)
+				nsName := collectResourceName(args, i+1, ctx)
+				if nsName.wordCount > 0 {
+					ctx.Namespace = nsName.name
+					*index += nsName.wordCount
+				}
 			}
 		}
 		return true
@@ -565,33 +1168,143 @@ func parsePrepositions(word string, args []string, index *int, ctx *Context) boo
 	return false
 }
 
+// collectSelector collects label selector terms starting at startIndex.
+// Terms may be joined by "and" or ",".
+// Returns the terms and the number of words consumed.
+func collectSelector(args []string, startIndex int) ([]string, int) {
+	var terms []string
+	i := startIndex
+
+	for {
+		term, consumed := parseSelectorTerm(args, i)
+		if consumed == 0 {
+			break
+		}
+		terms = append(terms, term)
+		i += consumed
+
+		// Continue only if another term follows the conjunction
+		if i < len(args) && (strings.ToLower(args[i]) == "and" || args[i] == ",") {
+			if _, next := parseSelectorTerm(args, i+1); next > 0 {
+				i++
+				continue
+			}
+		}
+		break
+	}
+
+	return terms, i - startIndex
+}
+
+// parseSelectorTerm parses a single selector term: "key=value", "key!=value",
+// "key is value", "key is not value" or "key = value".
+func parseSelectorTerm(args []string, i int) (string, int) {
+	if i >= len(args) {
+		return "", 0
+	}
+
+	word := strings.Trim(args[i], ",")
+	next := func(offset int) string {
+		if i+offset < len(args) {
+			return strings.ToLower(args[i+offset])
+		}
+		return ""
+	}
+
+	switch {
+	case strings.Contains(word, "=") && !strings.HasPrefix(word, "="):
+		return strings.Replace(word, "==", "=", 1), 1
+	case next(1) == "is" && next(2) == "not" && i+3 < len(args):
+		return word + "!=" + strings.Trim(args[i+3], ","), 4
+	case (next(1) == "is" || next(1) == "=" || next(1) == "equals") && i+2 < len(args):
+		return word + "=" + strings.Trim(args[i+2], ","), 3
+	}
+	return "", 0
+}
+
+// collectExclusions collects excluded terms starting at startIndex, joined by
+// "and", "or" or ",". Returns the terms and the number of words consumed.
+func collectExclusions(args []string, startIndex int) ([]string, int) {
+	var terms []string
+	i := startIndex
+
+	for i < len(args) {
+		term := strings.Trim(args[i], ",")
+		if term == "" || strings.HasPrefix(term, "-") || isBuiltinWord(strings.ToLower(term)) {
+			break
+		}
+		terms = append(terms, term)
+		i++
+
+		// "kube-system, monitoring" or "kube-system and monitoring"
+		if strings.HasSuffix(args[i-1], ",") {
+			continue
+		}
+		if i+1 < len(args) && (strings.ToLower(args[i]) == "and" || strings.ToLower(args[i]) == "or" || args[i] == ",") {
+			next := strings.ToLower(strings.Trim(args[i+1], ","))
+			if next != "" && !strings.HasPrefix(next, "-") && !isBuiltinWord(next) {
+				i++
+				continue
+			}
+		}
+		break
+	}
+
+	return terms, i - startIndex
+}
+
 // resourceNameResult holds the collected resource name and word count
 type resourceNameResult struct {
 	name      string
 	wordCount int
 }
 
+// nameTerminators are keywords and prepositions that end a resource name
+var nameTerminators = map[string]bool{
+	PrepIn: true, PrepFrom: true, PrepOf: true, PrepTo: true, PrepInto: true,
+	KwApp: true, KwPod: true, KwDeployment: true, KwService: true, KwNamespace: true, KwFile: true,
+	KwStatefulSet: true, KwDaemonSet: true, KwJob: true, KwCronJob: true,
+	KwContainer: true, "with": true, "follow": true, "prefix": true, "search": true, "find": true, "filter": true,
+	"grep": true, "max": true, "port": true, "label": true, "labels": true, "where": true,
+	"since": true, "last": true, "past": true, "before": true, "previous": true, CmdGet: true,
+	"regex": true, "ignoring": true, "except": true, "excluding": true, "exclude": true, "without": true, "but": true,
+}
+
+// scaleWords end a resource name in scale commands: "scale api up by 2"
+var scaleWords = map[string]bool{
+	"up": true, "down": true, "by": true, "back": true, "double": true, "half": true,
+}
+
+// endsName reports whether word ends a resource name in the command parsed so far.
+// Scaling and pod-picking words end names only in the commands that use them, so
+// "logs of order back office" keeps the whole name.
+func endsName(word string, ctx *Context) bool {
+	switch {
+	case nameTerminators[word]:
+		return true
+	case ctx.Command == CmdScale:
+		return scaleWords[word]
+	case picksPod(ctx.Command):
+		return word == "on" || pickWords[word] != "" || ordinalWords[word] > 0
+	}
+	return false
+}
+
 // collectResourceName collects consecutive words until hitting a keyword or preposition
 // Returns the collected name (space-separated) and the number of words consumed
-func collectResourceName(args []string, startIndex int) resourceNameResult {
+func collectResourceName(args []string, startIndex int, ctx *Context) resourceNameResult {
 	if startIndex >= len(args) {
 		return resourceNameResult{"", 0}
 	}
 
 	var words []string
-	keywords := map[string]bool{
-		PrepIn: true, PrepFrom: true, PrepOf: true, PrepTo: true, PrepInto: true,
-		KwApp: true, KwPod: true, KwDeployment: true, KwService: true, KwNamespace: true, KwFile: true,
-		"with": true, "follow": true, "prefix": true, "search": true, "find": true, "filter": true,
-		"grep": true, "max": true, "port": true,
-	}
 
 	for i := startIndex; i < len(args); i++ {
 		word := args[i]
 		wordLower := strings.ToLower(word)
 
 		// Stop at keywords, prepositions, or flags
-		if keywords[wordLower] || strings.HasPrefix(word, "-") || stopWords[wordLower] {
+		if endsName(wordLower, ctx) || strings.HasPrefix(word, "-") || stopWords[wordLower] {
 			break
 		}
 
@@ -625,6 +1338,8 @@ func inferNamespaceFromContext(word string, ctx *Context) bool {
 	if ctx.Namespace == "" && (ctx.Command == "pods" || ctx.Command == "deployments" ||
 		ctx.Command == "services" || ctx.Command == "nodes" || ctx.Command == "configmaps" ||
 		ctx.Command == "secrets" || ctx.Command == "ingresses" || ctx.Command == "pvcs" ||
+		ctx.Command == "statefulsets" || ctx.Command == "daemonsets" || ctx.Command == "jobs" || ctx.Command == "cronjobs" ||
+		ctx.Command == CmdResources ||
 		ctx.Command == "events" || ctx.Command == "status" || ctx.Command == "all") {
 		// Ensure it's not a flag or modifier we missed
 		if !strings.HasPrefix(word, "-") {
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// useResolver replaces the shared resolver for the duration of a test
+func useResolver(t *testing.T, r *ResourceResolver) {
+	t.Helper()
+	old := getResolver()
+	resolver = r
+	t.Cleanup(func() { resolver = old })
+}
+
+func TestParseLearnedResourceTypes(t *testing.T) {
+	useResolver(t, &ResourceResolver{patterns: &config.ClusterPatterns{
+		APIResources: []config.APIResource{
+			{Name: "rollouts", ShortNames: []string{"ro"}, Group: "argoproj.io", Kind: "Rollout", Namespaced: true},
+			{Name: "certificates", ShortNames: []string{"cert", "certs"}, Group: "cert-manager.io", Kind: "Certificate", Namespaced: true},
+			{Name: "virtualservices", ShortNames: []string{"vs"}, Group: "networking.istio.io", Kind: "VirtualService", Namespaced: true},
+			{Name: "leases", Group: "coordination.k8s.io", Kind: "Lease", Namespaced: true},
+		},
+	}})
+
+	tests := []struct {
+		name         string
+		args         []string
+		command      string
+		resourceType string
+		resourceName string
+		namespace    string
+	}{
+		{"list by plural", []string{"rollouts", "in", "prod"}, CmdResources, "rollouts.argoproj.io", "", "prod"},
+		{"get by short name", []string{"get", "certs", "in", "staging"}, CmdResources, "certificates.cert-manager.io", "", "staging"},
+		{"namespace after type", []string{"vs", "prod"}, CmdResources, "virtualservices.networking.istio.io", "", "prod"},
+		{"describe by short name", []string{"describe", "vs", "reviews", "in", "prod"}, "describe", "virtualservices.networking.istio.io", "reviews", "prod"},
+		{"describe by kind", []string{"describe", "rollout", "api"}, "describe", "rollouts.argoproj.io", "api", ""},
+		{"delete by short name", []string{"delete", "cert", "web-tls", "in", "prod"}, "delete", "certificates.cert-manager.io", "web-tls", "prod"},
+		{"rollout stays a command", []string{"rollout", "api"}, CmdRestart, "", "", ""},
+		{"built-in types win", []string{"describe", "pod", "api"}, "describe", "pod", "api", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, ctx.Command)
+			}
+			if ctx.ResourceType != tt.resourceType || ctx.ResourceName != tt.resourceName {
+				t.Errorf("expected %q %q, got %q %q", tt.resourceType, tt.resourceName, ctx.ResourceType, ctx.ResourceName)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestParseContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected Context
+	}{
+		{
+			name: "logs from container of app",
+			args: []string{"logs", "from", "container", "worker", "of", "api"},
+			expected: Context{
+				Command:   "logs",
+				AppName:   "api",
+				Container: "worker",
+			},
+		},
+		{
+			name: "shell into pod with container",
+			args: []string{"shell", "into", "api", "container", "app", "in", "prod"},
+			expected: Context{
+				Command:   "shell",
+				PodName:   "api",
+				Container: "app",
+				Namespace: "prod",
+			},
+		},
+		{
+			name: "logs of app from all containers",
+			args: []string{"logs", "of", "api", "from", "all", "containers"},
+			expected: Context{
+				Command:       "logs",
+				AppName:       "api",
+				AllContainers: true,
+			},
+		},
+		{
+			name: "all containers flag",
+			args: []string{"logs", "mypod", "--all-containers"},
+			expected: Context{
+				Command:       "logs",
+				PodName:       "mypod",
+				AllContainers: true,
+			},
+		},
+		{
+			name: "copy with container",
+			args: []string{"copy", "file", "local.txt", "to", "api-0:/tmp/x", "container", "app"},
+			expected: Context{
+				Command:    "copy",
+				SourcePath: "local.txt",
+				DestPath:   "api-0:/tmp/x",
+				Container:  "app",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.expected.Command {
+				t.Errorf("expected command %s, got %s", tt.expected.Command, ctx.Command)
+			}
+			if ctx.Container != tt.expected.Container {
+				t.Errorf("expected container %q, got %q", tt.expected.Container, ctx.Container)
+			}
+			if ctx.AllContainers != tt.expected.AllContainers {
+				t.Errorf("expected all containers %v, got %v", tt.expected.AllContainers, ctx.AllContainers)
+			}
+			if ctx.AppName != tt.expected.AppName || ctx.PodName != tt.expected.PodName {
+				t.Errorf("expected app %q pod %q, got app %q pod %q", tt.expected.AppName, tt.expected.PodName, ctx.AppName, ctx.PodName)
+			}
+			if ctx.Namespace != tt.expected.Namespace {
+				t.Errorf("expected namespace %q, got %q", tt.expected.Namespace, ctx.Namespace)
+			}
+			if ctx.SourcePath != tt.expected.SourcePath || ctx.DestPath != tt.expected.DestPath {
+				t.Errorf("expected paths %q -> %q, got %q -> %q", tt.expected.SourcePath, tt.expected.DestPath, ctx.SourcePath, ctx.DestPath)
+			}
+		})
+	}
+}
+
+func TestResolveContainerName(t *testing.T) {
+	r := &ResourceResolver{patterns: &config.ClusterPatterns{
+		AppLabels: map[string]string{"prod/api-7d9f-abcde": "api"},
+		Containers: map[string][]string{
+			"prod/api-7d9f-abcde": {"api", "istio-proxy", "log-shipper"},
+		},
+	}}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"istio-proxy", "istio-proxy"},
+		{"istio-prxy", "istio-proxy"},
+		{"unknown-sidecar", "unknown-sidecar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := r.ResolveContainerName(tt.input, "prod", "api", ""); got != tt.expected {
+				t.Errorf("ResolveContainerName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
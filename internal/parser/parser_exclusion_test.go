@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExclusions(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		command    string
+		exclusions []string
+		appName    string
+		namespace  string
+		searchTerm string
+	}{
+		{"pods except namespace", []string{"pods", "except", "kube-system"}, "pods", []string{"kube-system"}, "", "", ""},
+		{"several namespaces", []string{"pods", "except", "kube-system", "and", "monitoring"}, "pods", []string{"kube-system", "monitoring"}, "", "", ""},
+		{"comma separated", []string{"deployments", "excluding", "kube-system,", "monitoring"}, "deployments", []string{"kube-system", "monitoring"}, "", "", ""},
+		{"logs without text", []string{"logs", "from", "api", "without", "healthcheck"}, "logs", []string{"healthcheck"}, "api", "", ""},
+		{"logs without phrase and search", []string{`logs from api without "GET /ready" search error in prod`}, "logs", []string{"GET /ready"}, "api", "prod", "error"},
+		{"events excluding type", []string{"events", "excluding", "normal"}, "events", []string{"normal"}, "", "", ""},
+		{"but not", []string{"events", "in", "prod", "but", "not", "normal"}, "events", []string{"normal"}, "", "prod", ""},
+		{"stops at keyword", []string{"pods", "except", "kube-system", "in", "prod"}, "pods", []string{"kube-system"}, "", "prod", ""},
+		{"nothing to exclude", []string{"pods", "without", "in", "prod"}, "pods", nil, "", "prod", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, ctx.Command)
+			}
+			if !reflect.DeepEqual(ctx.Exclusions, tt.exclusions) {
+				t.Errorf("expected exclusions %q, got %q", tt.exclusions, ctx.Exclusions)
+			}
+			if ctx.AppName != tt.appName {
+				t.Errorf("expected app %q, got %q", tt.appName, ctx.AppName)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+			if ctx.SearchTerm != tt.searchTerm {
+				t.Errorf("expected search term %q, got %q", tt.searchTerm, ctx.SearchTerm)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestParseAllNamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		command    string
+		all        bool
+		namespace  string
+		searchTerm string
+	}{
+		{"everywhere", []string{"pods", "everywhere"}, "pods", true, "", ""},
+		{"across all namespaces", []string{"deployments", "across", "all", "namespaces"}, "deployments", true, "", ""},
+		{"in any namespace", []string{"services", "in", "any", "namespace"}, "services", true, "", ""},
+		{"all namespaces is not a listing of namespaces", []string{"pods", "in", "all", "namespaces"}, "pods", true, "", ""},
+		{"namespace first", []string{"in", "all", "namespaces", "get", "pods"}, "pods", true, "", ""},
+		{"kubectl flag", []string{"events", "-A"}, "events", true, "", ""},
+		{"metrics", []string{"check", "usage", "everywhere"}, "metrics", true, "", ""},
+		{"find by name", []string{"find", "api", "in", "any", "namespace"}, "all", true, "", "api"},
+		{"regular namespace", []string{"pods", "in", "prod"}, "pods", false, "prod", ""},
+		{"all containers unaffected", []string{"logs", "from", "api", "all", "containers"}, "logs", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, ctx.Command)
+			}
+			if ctx.AllNamespaces != tt.all {
+				t.Errorf("expected all namespaces %v, got %v", tt.all, ctx.AllNamespaces)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+			if ctx.SearchTerm != tt.searchTerm {
+				t.Errorf("expected search term %q, got %q", tt.searchTerm, ctx.SearchTerm)
+			}
+		})
+	}
+}
+
+func TestInferNamespaceFromUniqueMatch(t *testing.T) {
+	useMemory(t, nil)
+	useResolver(t, &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces:  []string{"payments", "prod", "staging"},
+		Deployments: []string{"payments/billing-api", "prod/web", "staging/web"},
+		Services:    []string{"payments/billing-db"},
+		Workloads:   map[string][]string{KwStatefulSet: {"data/postgres"}},
+		AppLabels:   map[string]string{"monitoring/grafana-7d9f-x2x": "grafana"},
+	}})
+
+	tests := []struct {
+		name      string
+		args      []string
+		namespace string
+		notes     []string
+	}{
+		{"deployment in one namespace", []string{"logs", "from", "billing-api"}, "payments",
+			[]string{"namespace payments: the only namespace with app billing-api"}},
+		{"scale by deployment", []string{"scale", "billing-api", "to", "3"}, "payments",
+			[]string{"namespace payments: the only namespace with deployment billing-api"}},
+		{"statefulset", []string{"restart", "sts", "postgres"}, "data",
+			[]string{"namespace data: the only namespace with statefulset postgres"}},
+		{"service", []string{"forward", "billing-db", "port", "5432"}, "payments",
+			[]string{"namespace payments: the only namespace with service billing-db"}},
+		{"app label", []string{"logs", "from", "app", "grafana"}, "monitoring",
+			[]string{"namespace monitoring: the only namespace with app grafana"}},
+		{"several namespaces stay unset", []string{"logs", "from", "app", "web"}, "", nil},
+		{"explicit namespace wins", []string{"logs", "from", "billing-api", "in", "prod"}, "prod", nil},
+		{"all namespaces", []string{"pods", "of", "app", "grafana", "everywhere"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+			if !reflect.DeepEqual(ctx.Notes, tt.notes) {
+				t.Errorf("expected notes %q, got %q", tt.notes, ctx.Notes)
+			}
+		})
+	}
+}
+
+func TestResolveAppNameStaysInNamespace(t *testing.T) {
+	r := &ResourceResolver{patterns: &config.ClusterPatterns{
+		Deployments: []string{"prod/web-prod", "staging/web-staging"},
+		Patterns:    []string{"{app}-{namespace}"},
+		CommonApps:  []string{"grafana"},
+		AppLabels:   map[string]string{"monitoring/grafana-7d9f-x2x": "grafana"},
+	}}
+
+	if got := r.ResolveAppName("grafna", "prod"); got != "grafna" {
+		t.Errorf("expected no app from another namespace, got %q", got)
+	}
+	if got := r.ResolveAppName("grafna", "monitoring"); got != "grafana" {
+		t.Errorf("expected the app in monitoring, got %q", got)
+	}
+	if got := r.ResolveAppName("web", "prod"); got != "web-prod" {
+		t.Errorf("expected the pattern match in prod, got %q", got)
+	}
+}
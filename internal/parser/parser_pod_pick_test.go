@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParsePodPick(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		command   string
+		app       string
+		pick      string
+		index     int
+		node      string
+		namespace string
+	}{
+		{"newest pod of app", []string{"shell", "into", "newest", "pod", "of", "api"}, CmdShell, "api", PickNewest, 0, "", ""},
+		{"ordinal before name", []string{"logs", "from", "the", "second", "api", "pod"}, CmdLogs, "api", "", 2, "", ""},
+		{"numeric ordinal", []string{"restart", "3rd", "pod", "of", "api", "in", "prod"}, CmdRestart, "api", "", 3, "", "prod"},
+		{"on node", []string{"shell", "into", "api", "on", "node", "ip-10-0-1-5"}, CmdShell, "api", "", 0, "ip-10-0-1-5", ""},
+		{"any ready", []string{"shell", "into", "any", "ready", "api", "pod"}, CmdShell, "api", PickReady, 0, "", ""},
+		{"oldest with namespace", []string{"shell", "into", "the", "oldest", "api", "pod", "in", "prod"}, CmdShell, "api", PickOldest, 0, "", "prod"},
+		{"time units stay time units", []string{"logs", "from", "api", "since", "1", "second"}, CmdLogs, "api", "", 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, ctx.Command)
+			}
+			if ctx.AppName != tt.app || ctx.PodName != "" {
+				t.Errorf("expected app %q and no pod, got app %q pod %q", tt.app, ctx.AppName, ctx.PodName)
+			}
+			if ctx.PodPick != tt.pick || ctx.PodIndex != tt.index || ctx.Node != tt.node {
+				t.Errorf("expected pick %q #%d on %q, got %q #%d on %q", tt.pick, tt.index, tt.node, ctx.PodPick, ctx.PodIndex, ctx.Node)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+		})
+	}
+}
+
+func TestScopedNameTerminators(t *testing.T) {
+	// Scaling and pod-picking words only end names in the commands that use them
+	tests := []struct {
+		name string
+		args []string
+		app  string
+	}{
+		{"scale word in logs", []string{"logs", "of", "order", "back", "office"}, "order back office"},
+		{"pick words in events", []string{"events", "of", "first", "responder", "on", "call"}, "first responder on call"},
+		{"ordinal after app", []string{"events", "of", "app", "second", "opinion"}, "second opinion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+			if ctx.AppName != tt.app {
+				t.Errorf("expected app %q, got %q", tt.app, ctx.AppName)
+			}
+		})
+	}
+}
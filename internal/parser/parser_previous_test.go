@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParsePreviousLogs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		previous  bool
+		appName   string
+		podName   string
+		namespace string
+		podStatus string
+	}{
+		{"crashed app", []string{"logs", "of", "the", "crashed", "api"}, true, "api", "", "", ""},
+		{"previous logs from app", []string{"previous", "logs", "from", "api", "in", "prod"}, true, "api", "", "prod", ""},
+		{"logs before restart", []string{"logs", "of", "api", "before", "restart"}, true, "api", "", "", ""},
+		{"logs before it crashed", []string{"logs", "from", "pod", "api-1", "before", "it", "crashed"}, true, "", "api-1", "", ""},
+		{"previous flag", []string{"logs", "api-1", "--previous"}, true, "", "api-1", "", ""},
+		{"plain logs", []string{"logs", "of", "api", "in", "prod"}, false, "api", "", "prod", ""},
+		{"crashing pods still filter", []string{"pods", "crashing", "in", "prod"}, false, "", "", "prod", "crashing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Previous != tt.previous {
+				t.Errorf("expected previous %v, got %v", tt.previous, ctx.Previous)
+			}
+			if ctx.AppName != tt.appName || ctx.PodName != tt.podName {
+				t.Errorf("expected app %q pod %q, got app %q pod %q", tt.appName, tt.podName, ctx.AppName, ctx.PodName)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+			if ctx.PodStatus != tt.podStatus {
+				t.Errorf("expected pod status %q, got %q", tt.podStatus, ctx.PodStatus)
+			}
+		})
+	}
+}
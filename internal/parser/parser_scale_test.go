@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseRelativeScale(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		scaleOp    string
+		replicas   string
+		deployment string
+		namespace  string
+	}{
+		{"up by", []string{"scale", "api", "up", "by", "2"}, ScaleUp, "2", "api", ""},
+		{"down by", []string{"scale", "api", "down", "by", "1", "in", "prod"}, ScaleDown, "1", "api", "prod"},
+		{"up without amount", []string{"scale", "api", "up"}, ScaleUp, "", "api", ""},
+		{"down to half", []string{"scale", "worker", "down", "to", "half"}, ScaleHalf, "", "worker", ""},
+		{"to double", []string{"scale", "worker", "to", "double"}, ScaleDouble, "", "worker", ""},
+		{"double as verb", []string{"double", "api", "in", "prod"}, ScaleDouble, "", "api", "prod"},
+		{"halve as verb", []string{"halve", "worker"}, ScaleHalf, "", "worker", ""},
+		{"back", []string{"scale", "api", "back"}, ScaleBack, "", "api", ""},
+		{"back to previous", []string{"scale", "deployment", "api", "back", "to", "previous", "in", "prod"}, ScaleBack, "", "api", "prod"},
+		{"absolute", []string{"scale", "api", "to", "3"}, "", "3", "api", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != CmdScale {
+				t.Errorf("expected command %q, got %q", CmdScale, ctx.Command)
+			}
+			if ctx.ScaleOp != tt.scaleOp || ctx.Replicas != tt.replicas {
+				t.Errorf("expected scale %q by %q, got %q by %q", tt.scaleOp, tt.replicas, ctx.ScaleOp, ctx.Replicas)
+			}
+			if ctx.DeploymentName != tt.deployment {
+				t.Errorf("expected deployment %q, got %q", tt.deployment, ctx.DeploymentName)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseLabelSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected Context
+	}{
+		{
+			name: "pods with label key=value",
+			args: []string{"pods", "with", "label", "tier=backend", "in", "prod"},
+			expected: Context{
+				Command:   "pods",
+				Selector:  "tier=backend",
+				Namespace: "prod",
+			},
+		},
+		{
+			name: "logs where key is value",
+			args: []string{"logs", "where", "team", "is", "payments"},
+			expected: Context{
+				Command:  "logs",
+				Selector: "team=payments",
+			},
+		},
+		{
+			name: "multiple terms joined by and",
+			args: []string{"get", "deployments", "where", "team", "is", "payments", "and", "tier", "is", "not", "frontend", "in", "qa"},
+			expected: Context{
+				Command:   "deployments",
+				Selector:  "team=payments,tier!=frontend",
+				Namespace: "qa",
+			},
+		},
+		{
+			name: "kubectl style flag",
+			args: []string{"pods", "-l", "app.kubernetes.io/name=api"},
+			expected: Context{
+				Command:  "pods",
+				Selector: "app.kubernetes.io/name=api",
+			},
+		},
+		{
+			name: "delete by label",
+			args: []string{"delete", "pods", "with", "label", "job=cleanup", "in", "dev"},
+			expected: Context{
+				Command:      "delete",
+				ResourceType: "pod",
+				Selector:     "job=cleanup",
+				Namespace:    "dev",
+			},
+		},
+		{
+			name: "restart app with extra label",
+			args: []string{"restart", "app", "api", "where", "track", "is", "canary"},
+			expected: Context{
+				Command:  "restart",
+				AppName:  "api",
+				Selector: "track=canary",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.expected.Command {
+				t.Errorf("expected command %s, got %s", tt.expected.Command, ctx.Command)
+			}
+			if ctx.Selector != tt.expected.Selector {
+				t.Errorf("expected selector %q, got %q", tt.expected.Selector, ctx.Selector)
+			}
+			if ctx.Namespace != tt.expected.Namespace {
+				t.Errorf("expected namespace %s, got %s", tt.expected.Namespace, ctx.Namespace)
+			}
+			if ctx.AppName != tt.expected.AppName {
+				t.Errorf("expected app name %s, got %s", tt.expected.AppName, ctx.AppName)
+			}
+			if ctx.ResourceType != tt.expected.ResourceType {
+				t.Errorf("expected resource type %s, got %s", tt.expected.ResourceType, ctx.ResourceType)
+			}
+		})
+	}
+}
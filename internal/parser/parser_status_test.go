@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParsePodStatusFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected Context
+	}{
+		{
+			name: "what pods are crashing",
+			args: []string{"show", "me", "what", "pods", "are", "crashing", "in", "prod"},
+			expected: Context{
+				Command:   "pods",
+				PodStatus: "crashing",
+				Namespace: "prod",
+			},
+		},
+		{
+			name: "pending pods",
+			args: []string{"pending", "pods", "in", "qa"},
+			expected: Context{
+				Command:   "pods",
+				PodStatus: "pending",
+				Namespace: "qa",
+			},
+		},
+		{
+			name: "not ready pods",
+			args: []string{"get", "pods", "not", "ready"},
+			expected: Context{
+				Command:   "pods",
+				PodStatus: "not-ready",
+			},
+		},
+		{
+			name: "evicted pods",
+			args: []string{"list", "evicted", "pods"},
+			expected: Context{
+				Command:   "pods",
+				PodStatus: "evicted",
+			},
+		},
+		{
+			name: "restarted more than N times",
+			args: []string{"pods", "restarted", "more", "than", "3", "times", "in", "prod"},
+			expected: Context{
+				Command:     "pods",
+				MinRestarts: 4,
+				Namespace:   "prod",
+			},
+		},
+		{
+			name: "failed pods of app",
+			args: []string{"failed", "pods", "of", "api"},
+			expected: Context{
+				Command:   "pods",
+				PodStatus: "failed",
+				AppName:   "api",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.expected.Command {
+				t.Errorf("expected command %s, got %s", tt.expected.Command, ctx.Command)
+			}
+			if ctx.PodStatus != tt.expected.PodStatus {
+				t.Errorf("expected pod status %q, got %q", tt.expected.PodStatus, ctx.PodStatus)
+			}
+			if ctx.MinRestarts != tt.expected.MinRestarts {
+				t.Errorf("expected min restarts %d, got %d", tt.expected.MinRestarts, ctx.MinRestarts)
+			}
+			if ctx.Namespace != tt.expected.Namespace {
+				t.Errorf("expected namespace %s, got %s", tt.expected.Namespace, ctx.Namespace)
+			}
+			if ctx.AppName != tt.expected.AppName {
+				t.Errorf("expected app name %s, got %s", tt.expected.AppName, ctx.AppName)
+			}
+		})
+	}
+}
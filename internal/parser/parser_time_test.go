@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeWindows(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected Context
+	}{
+		{
+			name: "since N minutes ago",
+			args: []string{"logs", "of", "api", "since", "15", "minutes", "ago"},
+			expected: Context{
+				Command: "logs",
+				AppName: "api",
+				Since:   "15m",
+			},
+		},
+		{
+			name: "in the last hour",
+			args: []string{"logs", "of", "api", "in", "prod", "in", "the", "last", "hour"},
+			expected: Context{
+				Command:   "logs",
+				AppName:   "api",
+				Namespace: "prod",
+				Since:     "1h",
+			},
+		},
+		{
+			name: "past N days",
+			args: []string{"events", "in", "prod", "past", "2", "days"},
+			expected: Context{
+				Command:   "events",
+				Namespace: "prod",
+				Since:     "48h",
+			},
+		},
+		{
+			name: "compact duration",
+			args: []string{"logs", "mypod", "since", "30m"},
+			expected: Context{
+				Command: "logs",
+				PodName: "mypod",
+				Since:   "30m",
+			},
+		},
+		{
+			name: "since clock time",
+			args: []string{"events", "in", "prod", "since", "10:30"},
+			expected: Context{
+				Command:   "events",
+				Namespace: "prod",
+				SinceTime: "2024-05-01T10:30:00Z",
+			},
+		},
+		{
+			name: "clock time in the future means yesterday",
+			args: []string{"events", "since", "13:15"},
+			expected: Context{
+				Command:   "events",
+				SinceTime: "2024-04-30T13:15:00Z",
+			},
+		},
+		{
+			name: "get last N is still tail lines",
+			args: []string{"logs", "from", "pod", "mypod", "get", "last", "100"},
+			expected: Context{
+				Command:   "logs",
+				PodName:   "mypod",
+				TailLines: 100,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.expected.Command {
+				t.Errorf("expected command %s, got %s", tt.expected.Command, ctx.Command)
+			}
+			if ctx.Since != tt.expected.Since {
+				t.Errorf("expected since %q, got %q", tt.expected.Since, ctx.Since)
+			}
+			if ctx.SinceTime != tt.expected.SinceTime {
+				t.Errorf("expected since time %q, got %q", tt.expected.SinceTime, ctx.SinceTime)
+			}
+			if ctx.Namespace != tt.expected.Namespace {
+				t.Errorf("expected namespace %q, got %q", tt.expected.Namespace, ctx.Namespace)
+			}
+			if ctx.AppName != tt.expected.AppName || ctx.PodName != tt.expected.PodName {
+				t.Errorf("expected app %q pod %q, got app %q pod %q", tt.expected.AppName, tt.expected.PodName, ctx.AppName, ctx.PodName)
+			}
+			if ctx.TailLines != tt.expected.TailLines {
+				t.Errorf("expected tail lines %d, got %d", tt.expected.TailLines, ctx.TailLines)
+			}
+		})
+	}
+}
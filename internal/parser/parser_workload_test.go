@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestParseWorkloadKinds(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		command   string
+		workload  string
+		kind      string
+		namespace string
+	}{
+		{"restart sts", []string{"restart", "sts", "db", "in", "prod"}, CmdRestart, "db", KwStatefulSet, "prod"},
+		{"restart daemonset", []string{"restart", "daemonset", "fluentd"}, CmdRestart, "fluentd", KwDaemonSet, ""},
+		{"kind after name", []string{"restart", "fluentd", "ds"}, CmdRestart, "fluentd", KwDaemonSet, ""},
+		{"scale statefulset", []string{"scale", "statefulset", "db", "to", "3"}, CmdScale, "db", KwStatefulSet, ""},
+		{"logs from job", []string{"logs", "from", "job", "migrate", "in", "prod"}, CmdLogs, "migrate", KwJob, "prod"},
+		{"rollback ds", []string{"rollback", "ds", "fluentd"}, CmdRollback, "fluentd", KwDaemonSet, ""},
+		{"deployment keeps no kind", []string{"restart", "deployment", "api"}, CmdRestart, "api", "", ""},
+		{"list statefulsets", []string{"get", "sts", "in", "prod"}, "statefulsets", "", "", "prod"},
+		{"list cronjobs", []string{"cj", "batch"}, "cronjobs", "", "", "batch"},
+		{"list jobs", []string{"show", "jobs"}, "jobs", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.Command != tt.command {
+				t.Errorf("expected command %q, got %q", tt.command, ctx.Command)
+			}
+			if ctx.DeploymentName != tt.workload || ctx.WorkloadKind != tt.kind {
+				t.Errorf("expected %q of kind %q, got %q of kind %q", tt.workload, tt.kind, ctx.DeploymentName, ctx.WorkloadKind)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+		})
+	}
+}
+
+func TestResolveWorkloadKind(t *testing.T) {
+	r := &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces:  []string{"prod", "logging"},
+		Deployments: []string{"prod/api", "prod/web"},
+		Pods:        []string{"prod/postgres-0", "prod/api-7d9f-x2k4"},
+		Workloads: map[string][]string{
+			KwStatefulSet: {"prod/postgres", "prod/web"},
+			KwDaemonSet:   {"logging/fluentd"},
+			KwCronJob:     {"prod/nightly-backup"},
+		},
+	}}
+
+	tests := []struct {
+		name     string
+		ctx      *Context
+		workload string
+		kind     string
+		pod      string
+	}{
+		{
+			name:     "statefulset by deployment name",
+			ctx:      &Context{Command: CmdScale, DeploymentName: "postgres"},
+			workload: "postgres",
+			kind:     KwStatefulSet,
+		},
+		{
+			name:     "daemonset with a typo",
+			ctx:      &Context{Command: CmdRollback, DeploymentName: "fluentdd"},
+			workload: "fluentd",
+			kind:     KwDaemonSet,
+		},
+		{
+			name:     "restart target that is no pod",
+			ctx:      &Context{Command: CmdRestart, PodName: "nightly backup"},
+			workload: "nightly-backup",
+			kind:     KwCronJob,
+		},
+		{
+			name: "existing pod stays a pod",
+			ctx:  &Context{Command: CmdRestart, PodName: "postgres-0"},
+			pod:  "postgres-0",
+		},
+		{
+			name:     "deployments win ties",
+			ctx:      &Context{Command: CmdRestart, DeploymentName: "web"},
+			workload: "web",
+		},
+		{
+			name:     "explicit kind resolves within the kind",
+			ctx:      &Context{Command: CmdRestart, DeploymentName: "postgress", WorkloadKind: KwStatefulSet},
+			workload: "postgres",
+			kind:     KwStatefulSet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolveWorkload(tt.ctx, r, nil)
+
+			if tt.ctx.DeploymentName != tt.workload || tt.ctx.WorkloadKind != tt.kind {
+				t.Errorf("expected %q of kind %q, got %q of kind %q", tt.workload, tt.kind, tt.ctx.DeploymentName, tt.ctx.WorkloadKind)
+			}
+			if tt.ctx.PodName != tt.pod {
+				t.Errorf("expected pod %q, got %q", tt.pod, tt.ctx.PodName)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import "strings"
+
+// maxGeneratedSuffixes is how many generated segments a stale pod name may lose:
+// the pod's random suffix and its ReplicaSet's pod template hash
+const maxGeneratedSuffixes = 2
+
+// generatedAlphabet holds the characters Kubernetes draws pod suffixes and pod
+// template hashes from; it has no vowels, so words of a workload name rarely fit
+const generatedAlphabet = "bcdfghjklmnpqrstvwxz0123456789"
+
+// podOwner returns the workload owning the learned pod called name, as "kind/name",
+// and the pod's namespace. A name that is not a learned pod is traced through the
+// learned pods it names up to generated suffixes, e.g. "api-7d9f8b6c5" or "api" for
+// "api-7d9f8b6c5-x2x4z", and then with its own generated suffixes dropped, so that
+// a pod of an older rollout still leads to its deployment. The pods found must
+// share one owner.
+func (r *ResourceResolver) podOwner(name string, namespace string) (string, string) {
+	if len(r.patterns.Owners) == 0 || name == "" {
+		return "", ""
+	}
+	idx := r.index(KwPod)
+
+	if matches := idx.exact(name, namespace); len(matches) > 0 {
+		return r.sharedOwner(matches)
+	}
+
+	prefix := name
+	for trimmed := 0; ; trimmed++ {
+		if owner, ns := r.sharedOwner(podsNamedAfter(idx, prefix, namespace)); owner != "" {
+			return owner, ns
+		}
+		base, suffix, ok := cutLastSegment(prefix)
+		if !ok || trimmed == maxGeneratedSuffixes || !generatedSuffix(suffix) {
+			return "", ""
+		}
+		prefix = base
+	}
+}
+
+// sharedOwner returns the owner and namespace shared by every pod, or "" when the
+// pods have several owners, live in several namespaces or have no learned owner
+func (r *ResourceResolver) sharedOwner(pods []Candidate) (string, string) {
+	owner, ns := "", ""
+	for i, p := range pods {
+		podOwner := r.patterns.Owners[p.Namespace+"/"+p.Name]
+		if podOwner == "" || (i > 0 && (podOwner != owner || p.Namespace != ns)) {
+			return "", ""
+		}
+		owner, ns = podOwner, p.Namespace
+	}
+	return owner, ns
+}
+
+// podsNamedAfter returns the learned pods named prefix followed only by generated
+// segments, ignoring case: "api" finds api-7d9f8b6c5-x2x4z but not api-gateway-5c6d7f8b9-qwrtz
+func podsNamedAfter(idx *nameIndex, prefix string, namespace string) []Candidate {
+	lower := strings.ToLower(prefix) + "-"
+	var pods []Candidate
+	for _, entry := range idx.startingWith(lower[0], namespace) {
+		rest, ok := strings.CutPrefix(entry.lower, lower)
+		if ok && allGenerated(rest) {
+			pods = append(pods, Candidate{Name: entry.name, Namespace: entry.namespace})
+		}
+	}
+	return pods
+}
+
+// allGenerated reports whether every hyphen-separated segment of name is generated
+func allGenerated(name string) bool {
+	for _, segment := range strings.Split(name, "-") {
+		if !generatedSuffix(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+// cutLastSegment splits "api-7d9f8b6c5" into "api" and "7d9f8b6c5"
+func cutLastSegment(name string) (string, string, bool) {
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// generatedSuffix reports whether a name segment looks generated by a controller:
+// a random pod suffix, a pod template hash or a StatefulSet ordinal
+func generatedSuffix(segment string) bool {
+	segment = strings.ToLower(segment)
+	return segment != "" && len(segment) <= 10 && strings.Trim(segment, generatedAlphabet) == ""
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestResolvePodOwner(t *testing.T) {
+	useMemory(t, nil)
+	useResolver(t, &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces:  []string{"prod", "staging"},
+		Deployments: []string{"prod/api", "prod/api-gateway", "staging/api"},
+		Workloads:   map[string][]string{KwStatefulSet: {"prod/db"}},
+		Pods: []string{
+			"prod/api-7d9f8b6c5-x2x4z", "prod/api-7d9f8b6c5-k8s2p", "prod/api-gateway-5c6d7f8b9-qwrtz",
+			"prod/db-0", "staging/api-6b7c8d9f4-mnbvc", "prod/static-web",
+		},
+		Owners: map[string]string{
+			"prod/api-7d9f8b6c5-x2x4z":         "deployment/api",
+			"prod/api-7d9f8b6c5-k8s2p":         "deployment/api",
+			"prod/api-gateway-5c6d7f8b9-qwrtz": "deployment/api-gateway",
+			"prod/db-0":                        "statefulset/db",
+			"staging/api-6b7c8d9f4-mnbvc":      "deployment/api",
+		},
+	}})
+
+	tests := []struct {
+		name      string
+		args      []string
+		pod       string
+		owner     string
+		namespace string
+		notes     []string
+	}{
+		{"learned pod", []string{"shell", "into", "pod", "api-7d9f8b6c5-x2x4z"},
+			"api-7d9f8b6c5-x2x4z", "deployment/api", "prod",
+			[]string{"namespace prod: the only namespace with pod api-7d9f8b6c5-x2x4z"}},
+		{"partial name", []string{"shell", "into", "pod", "api-7d9f8b6c5", "in", "prod"},
+			"api-7d9f8b6c5", "deployment/api", "prod", nil},
+		{"partial name picks the namespace", []string{"shell", "into", "pod", "api-7d9f8b6c5"},
+			"api-7d9f8b6c5", "deployment/api", "prod",
+			[]string{"namespace prod: where deployment api owns pod api-7d9f8b6c5"}},
+		{"replaced pod", []string{"shell", "into", "pod", "api-7d9f8b6c5-zzzzz", "in", "prod"},
+			"api-7d9f8b6c5-zzzzz", "deployment/api", "prod", nil},
+		{"new rollout", []string{"shell", "into", "pod", "api-5f4d3c2b1-hjklm", "in", "prod"},
+			"api-5f4d3c2b1-hjklm", "deployment/api", "prod", nil},
+		{"statefulset ordinal", []string{"shell", "into", "pod", "db-123", "in", "prod"},
+			"db-123", "statefulset/db", "prod", nil},
+		{"workload name", []string{"shell", "into", "pod", "api", "in", "prod"},
+			"api", "deployment/api", "prod", nil},
+		{"several namespaces", []string{"shell", "into", "pod", "api"},
+			"api", "", "", nil},
+		{"word segments are kept", []string{"shell", "into", "pod", "api-gateway-old", "in", "prod"},
+			"api-gateway-old", "", "prod", nil},
+		{"pod without owner", []string{"shell", "into", "pod", "static-web"},
+			"static-web", "", "prod", []string{"namespace prod: the only namespace with pod static-web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+			if ctx.PodName != tt.pod || ctx.PodOwner != tt.owner || ctx.Namespace != tt.namespace {
+				t.Errorf("expected pod %q of %q in %q, got pod %q of %q in %q",
+					tt.pod, tt.owner, tt.namespace, ctx.PodName, ctx.PodOwner, ctx.Namespace)
+			}
+			if !reflect.DeepEqual(ctx.Notes, tt.notes) {
+				t.Errorf("expected notes %q, got %q", tt.notes, ctx.Notes)
+			}
+		})
+	}
+}
+
+func TestGeneratedSuffix(t *testing.T) {
+	for segment, expected := range map[string]bool{
+		"7d9f8b6c5": true, "x2x4z": true, "0": true, "12": true,
+		"api": false, "gateway": false, "worker": false, "": false, "7d9f8b6c5d4f": false,
+	} {
+		if got := generatedSuffix(segment); got != expected {
+			t.Errorf("generatedSuffix(%q) = %v, want %v", segment, got, expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Ways to pick one pod of an app
+const (
+	PickNewest = "newest"
+	PickOldest = "oldest"
+	PickReady  = "ready"
+)
+
+// pickWords map words that pick a pod by age or readiness
+var pickWords = map[string]string{
+	"newest": PickNewest, "latest": PickNewest, "youngest": PickNewest,
+	"oldest": PickOldest,
+	"ready":  PickReady, "any": PickReady,
+}
+
+// ordinalWords map spelled-out ordinals to 1-based pod positions
+var ordinalWords = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+}
+
+// picksPod reports whether cmd acts on a single pod that can be picked from an app
+func picksPod(cmd string) bool {
+	return cmd == CmdLogs || cmd == CmdShell || cmd == CmdRestart
+}
+
+// parsePodPick handles "newest pod", "the second api pod", "any ready pod" and
+// "on node ip-10-0-1-5" for commands that act on one pod
+func parsePodPick(word string, args []string, index *int, ctx *Context) bool {
+	if !picksPod(ctx.Command) {
+		return false
+	}
+	i := *index
+
+	if word == "on" {
+		// "shell into api on node ip-10-0-1-5"
+		if i+2 < len(args) && strings.ToLower(args[i+1]) == "node" {
+			ctx.Node = args[i+2]
+			*index += 2
+			return true
+		}
+		return false
+	}
+
+	if pick, ok := pickWords[word]; ok {
+		ctx.PodPick = pick
+		// "any ready pod"
+		if word == "any" && i+1 < len(args) && strings.ToLower(args[i+1]) == "ready" {
+			*index++
+		}
+		return true
+	}
+
+	if n := ordinal(word); n > 0 {
+		ctx.PodIndex = n
+		return true
+	}
+	return false
+}
+
+// ordinal returns the position named by "second" or "2nd", or 0
+func ordinal(word string) int {
+	if n, ok := ordinalWords[word]; ok {
+		return n
+	}
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if digits, ok := strings.CutSuffix(word, suffix); ok {
+			if n, err := strconv.Atoi(digits); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// HasPodPick reports whether the command picks one pod of an app instead of naming it
+func (ctx *Context) HasPodPick() bool {
+	return ctx.PodPick != "" || ctx.PodIndex > 0 || ctx.Node != ""
+}
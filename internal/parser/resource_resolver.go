@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/geminal/skube/internal/config"
 )
@@ -9,6 +11,9 @@ import (
 // ResourceResolver helps match user input to actual cluster resources
 type ResourceResolver struct {
 	patterns *config.ClusterPatterns
+
+	indexMu sync.Mutex
+	indexes map[string]*nameIndex // by kind, created on first lookup
 }
 
 // NewResourceResolver creates a new resource resolver with cluster patterns
@@ -35,31 +40,36 @@ func NewResourceResolver() *ResourceResolver {
 
 // ResolveAppName attempts to match user input to an actual app/deployment name
 func (r *ResourceResolver) ResolveAppName(input string, namespace string) string {
+	return r.resolveAppName(input, namespace).Output
+}
+
+// resolveAppName is ResolveAppName, also reporting how the name was matched
+func (r *ResourceResolver) resolveAppName(input string, namespace string) Resolution {
 	if input == "" {
-		return input
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
 	}
 
 	// Try multiple naming conventions
-	variants := generateNamingVariants(input)
+	variants := r.variants(input)
 
 	// Try exact match first for each variant
 	for _, variant := range variants {
 		if match := r.findExactMatch(variant, namespace); match != "" {
-			return match
+			return exactResolution(input, variant, match)
 		}
 	}
 
 	// Try fuzzy matching on deployments with all variants
 	for _, variant := range variants {
-		if match := r.fuzzyMatchDeployment(variant, namespace); match != "" {
-			return match
+		if matches := r.index(KwDeployment).closest(variant, namespace); len(matches) > 0 {
+			return r.chooseResolution(input, variant, "deployment", namespace, MatchFuzzy, matches)
 		}
 	}
 
 	// Try fuzzy matching on common apps
 	for _, variant := range variants {
-		if match := r.fuzzyMatchApp(variant); match != "" {
-			return match
+		if match := r.fuzzyMatchApp(variant, namespace); match != "" {
+			return fuzzyResolution(input, variant, match)
 		}
 	}
 
@@ -67,69 +77,172 @@ func (r *ResourceResolver) ResolveAppName(input string, namespace string) string
 	if namespace != "" {
 		for _, variant := range variants {
 			if match := r.patternBasedMatch(variant, namespace); match != "" {
-				return match
+				return Resolution{Input: input, Output: match, Method: MatchPattern, Variant: variant}
 			}
 		}
 	}
 
+	// Try deployments containing the name as whole segments ("api" -> "api-gateway")
+	if matches := r.index(KwDeployment).segmentMatches(variants[0], namespace); len(matches) > 0 {
+		return r.chooseResolution(input, variants[0], "deployment", namespace, MatchSubstring, matches)
+	}
+
+	// Try deployments the name abbreviates ("agw" -> "api-gateway")
+	if matches := r.abbreviationMatches(KwDeployment, input, namespace); len(matches) > 0 {
+		return r.chooseResolution(input, variants[0], "deployment", namespace, MatchAbbreviation, matches)
+	}
+
 	// Return the first variant (hyphen-separated) as fallback
-	return variants[0]
+	return Resolution{Input: input, Output: variants[0], Method: MatchFallback, Variant: variants[0]}
+}
+
+// resolveWorkloadName resolves input among the learned workloads of kind,
+// which is a deployment when kind is empty
+func (r *ResourceResolver) resolveWorkloadName(kind string, input string, namespace string) Resolution {
+	if kind == "" || kind == KwDeployment {
+		return r.resolveAppName(input, namespace)
+	}
+	if input == "" {
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
+	}
+
+	entries := r.index(kind)
+	variants := r.variants(input)
+
+	for _, variant := range variants {
+		if match := entries.findExact(variant, namespace); match != "" {
+			return exactResolution(input, variant, match)
+		}
+	}
+
+	for _, variant := range variants {
+		if matches := entries.closest(variant, namespace); len(matches) > 0 {
+			return r.chooseResolution(input, variant, kind, namespace, MatchFuzzy, matches)
+		}
+	}
+
+	if matches := entries.segmentMatches(variants[0], namespace); len(matches) > 0 {
+		return r.chooseResolution(input, variants[0], kind, namespace, MatchSubstring, matches)
+	}
+
+	if matches := r.abbreviationMatches(kind, input, namespace); len(matches) > 0 {
+		return r.chooseResolution(input, variants[0], kind, namespace, MatchAbbreviation, matches)
+	}
+
+	return Resolution{Input: input, Output: variants[0], Method: MatchFallback, Variant: variants[0]}
+}
+
+// resolveWorkloadKind finds the workload kind other than deployment that input names.
+// Deployments win: ok is false when input matches a deployment at least as well
+// as any other workload, or matches none.
+func (r *ResourceResolver) resolveWorkloadKind(input string, namespace string) (string, Resolution, bool) {
+	if input == "" || len(r.patterns.Workloads) == 0 {
+		return "", Resolution{}, false
+	}
+	variants := r.variants(input)
+
+	for _, variant := range variants {
+		if r.findExactMatch(variant, namespace) != "" {
+			return "", Resolution{}, false
+		}
+	}
+	for _, kind := range workloadKinds {
+		for _, variant := range variants {
+			if match := r.index(kind).findExact(variant, namespace); match != "" {
+				return kind, exactResolution(input, variant, match), true
+			}
+		}
+	}
+
+	for _, variant := range variants {
+		if len(r.index(KwDeployment).closest(variant, namespace)) > 0 {
+			return "", Resolution{}, false
+		}
+	}
+	for _, kind := range workloadKinds {
+		for _, variant := range variants {
+			if matches := r.index(kind).closest(variant, namespace); len(matches) > 0 {
+				return kind, r.chooseResolution(input, variant, kind, namespace, MatchFuzzy, matches), true
+			}
+		}
+	}
+
+	return "", Resolution{}, false
 }
 
 // ResolveServiceName attempts to match user input to an actual service name
 func (r *ResourceResolver) ResolveServiceName(input string, namespace string) string {
+	return r.resolveServiceName(input, namespace).Output
+}
+
+// resolveServiceName is ResolveServiceName, also reporting how the name was matched
+func (r *ResourceResolver) resolveServiceName(input string, namespace string) Resolution {
 	if input == "" {
-		return input
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
 	}
 
-	variants := generateNamingVariants(input)
+	variants := r.variants(input)
 
 	// Try exact match for each variant
 	for _, variant := range variants {
 		if match := r.findExactServiceMatch(variant, namespace); match != "" {
-			return match
+			return exactResolution(input, variant, match)
 		}
 	}
 
 	// Try fuzzy matching
 	for _, variant := range variants {
-		if match := r.fuzzyMatchService(variant, namespace); match != "" {
-			return match
+		if matches := r.index(KwService).closest(variant, namespace); len(matches) > 0 {
+			return r.chooseResolution(input, variant, "service", namespace, MatchFuzzy, matches)
 		}
 	}
 
-	return variants[0]
+	if matches := r.abbreviationMatches(KwService, input, namespace); len(matches) > 0 {
+		return r.chooseResolution(input, variants[0], "service", namespace, MatchAbbreviation, matches)
+	}
+
+	return Resolution{Input: input, Output: variants[0], Method: MatchFallback, Variant: variants[0]}
 }
 
 // ResolvePodName attempts to match user input to an actual pod name
 func (r *ResourceResolver) ResolvePodName(input string, namespace string) string {
+	return r.resolvePodName(input, namespace).Output
+}
+
+// resolvePodName is ResolvePodName, also reporting how the name was matched
+func (r *ResourceResolver) resolvePodName(input string, namespace string) Resolution {
 	if input == "" {
-		return input
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
 	}
 
-	variants := generateNamingVariants(input)
+	variants := r.variants(input)
 
 	// Try exact match
 	for _, variant := range variants {
 		if match := r.findExactPodMatch(variant, namespace); match != "" {
-			return match
+			return exactResolution(input, variant, match)
 		}
 	}
 
 	// Try fuzzy matching
 	for _, variant := range variants {
 		if match := r.fuzzyMatchPod(variant, namespace); match != "" {
-			return match
+			return fuzzyResolution(input, variant, match)
 		}
 	}
 
-	return variants[0]
+	return Resolution{Input: input, Output: variants[0], Method: MatchFallback, Variant: variants[0]}
 }
 
 // ResolveNamespace attempts to match user input to an actual namespace
 func (r *ResourceResolver) ResolveNamespace(input string) string {
+	return r.resolveNamespace(input).Output
+}
+
+// resolveNamespace is ResolveNamespace, also reporting how the name was matched
+func (r *ResourceResolver) resolveNamespace(input string) Resolution {
 	if input == "" {
-		return input
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
 	}
 
 	inputLower := strings.ToLower(input)
@@ -137,181 +250,166 @@ func (r *ResourceResolver) ResolveNamespace(input string) string {
 	// Try exact match
 	for _, ns := range r.patterns.Namespaces {
 		if strings.ToLower(ns) == inputLower {
-			return ns
+			return exactResolution(input, input, ns)
 		}
 	}
 
 	// Try fuzzy match
 	if match, ok := FuzzyMatchWithThreshold(input, r.patterns.Namespaces); ok {
-		return match
+		return fuzzyResolution(input, input, match)
 	}
 
-	return input
+	return Resolution{Input: input, Output: input, Method: MatchUnchanged}
 }
 
-// findExactMatch looks for exact match in deployments
-func (r *ResourceResolver) findExactMatch(name string, namespace string) string {
-	nameLower := strings.ToLower(name)
-
-	for _, deployment := range r.patterns.Deployments {
-		parts := strings.Split(deployment, "/")
-		if len(parts) != 2 {
-			continue
-		}
+// ResolveContainerName attempts to match user input to a container learned for the app or pod.
+// Returns the input unchanged if no containers are known or nothing matches.
+func (r *ResourceResolver) ResolveContainerName(input string, namespace string, app string, pod string) string {
+	return r.resolveContainerName(input, namespace, app, pod).Output
+}
 
-		ns := parts[0]
-		depName := parts[1]
+// resolveContainerName is ResolveContainerName, also reporting how the name was matched
+func (r *ResourceResolver) resolveContainerName(input string, namespace string, app string, pod string) Resolution {
+	if input == "" {
+		return Resolution{Input: input, Output: input, Method: MatchUnchanged}
+	}
 
-		// If namespace is specified, only match in that namespace
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
+	containers := r.patterns.ContainersFor(namespace, app, pod)
+	for _, container := range containers {
+		if strings.EqualFold(container, input) {
+			return exactResolution(input, input, container)
 		}
+	}
 
-		if strings.ToLower(depName) == nameLower {
-			return depName
-		}
+	if match, ok := FuzzyMatchWithThreshold(input, containers); ok {
+		return fuzzyResolution(input, input, match)
 	}
 
-	return ""
+	return Resolution{Input: input, Output: input, Method: MatchUnchanged}
+}
+
+// findExactMatch looks for exact match in deployments
+func (r *ResourceResolver) findExactMatch(name string, namespace string) string {
+	return r.index(KwDeployment).findExact(name, namespace)
 }
 
 // findExactServiceMatch looks for exact match in services
 func (r *ResourceResolver) findExactServiceMatch(name string, namespace string) string {
-	nameLower := strings.ToLower(name)
-
-	for _, service := range r.patterns.Services {
-		parts := strings.Split(service, "/")
-		if len(parts) != 2 {
-			continue
-		}
-
-		ns := parts[0]
-		svcName := parts[1]
-
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
-		}
-
-		if strings.ToLower(svcName) == nameLower {
-			return svcName
-		}
-	}
-
-	return ""
+	return r.index(KwService).findExact(name, namespace)
 }
 
 // findExactPodMatch looks for exact match in pods
 func (r *ResourceResolver) findExactPodMatch(name string, namespace string) string {
-	nameLower := strings.ToLower(name)
-
-	for _, pod := range r.patterns.Pods {
-		parts := strings.Split(pod, "/")
-		if len(parts) != 2 {
-			continue
-		}
-
-		ns := parts[0]
-		podName := parts[1]
-
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
-		}
+	return r.index(KwPod).findExact(name, namespace)
+}
 
-		if strings.ToLower(podName) == nameLower {
-			return podName
-		}
+// findExact returns the name of the first entry called name, ignoring case, or ""
+func (idx *nameIndex) findExact(name string, namespace string) string {
+	if matches := idx.exact(name, namespace); len(matches) > 0 {
+		return matches[0].Name
 	}
-
 	return ""
 }
 
-// fuzzyMatchDeployment tries fuzzy matching on deployment names
-func (r *ResourceResolver) fuzzyMatchDeployment(name string, namespace string) string {
-	var candidates []string
-
-	for _, deployment := range r.patterns.Deployments {
-		parts := strings.Split(deployment, "/")
-		if len(parts) != 2 {
-			continue
-		}
-
-		ns := parts[0]
-		depName := parts[1]
-
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
-		}
-
-		candidates = append(candidates, depName)
+// fuzzyMatchPod tries fuzzy matching on pod names
+func (r *ResourceResolver) fuzzyMatchPod(name string, namespace string) string {
+	if matches := r.index(KwPod).closest(name, namespace); len(matches) > 0 {
+		return matches[0].Name
 	}
+	return ""
+}
 
-	if match, ok := FuzzyMatchWithThreshold(name, candidates); ok {
-		return match
+// fuzzyMatchApp tries fuzzy matching on common app names, limited to the
+// apps with pods in namespace when it is set
+func (r *ResourceResolver) fuzzyMatchApp(name string, namespace string) string {
+	kind := indexCommonApps
+	if namespace != "" {
+		kind = KwApp
+	}
+	if matches := r.index(kind).closest(name, namespace); len(matches) > 0 {
+		return matches[0].Name
 	}
-
 	return ""
 }
 
-// fuzzyMatchService tries fuzzy matching on service names
-func (r *ResourceResolver) fuzzyMatchService(name string, namespace string) string {
-	var candidates []string
+// indexCommonApps indexes CommonApps, which have no namespace
+const indexCommonApps = "commonApps"
 
-	for _, service := range r.patterns.Services {
-		parts := strings.Split(service, "/")
-		if len(parts) != 2 {
-			continue
-		}
+// index returns the name index of kind, creating it on first use
+func (r *ResourceResolver) index(kind string) *nameIndex {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
 
-		ns := parts[0]
-		svcName := parts[1]
-
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
-		}
-
-		candidates = append(candidates, svcName)
+	if idx, ok := r.indexes[kind]; ok {
+		return idx
 	}
-
-	if match, ok := FuzzyMatchWithThreshold(name, candidates); ok {
-		return match
+	if r.indexes == nil {
+		r.indexes = map[string]*nameIndex{}
 	}
-
-	return ""
+	idx := newNameIndex(r.indexEntries(kind))
+	r.indexes[kind] = idx
+	return idx
 }
 
-// fuzzyMatchPod tries fuzzy matching on pod names
-func (r *ResourceResolver) fuzzyMatchPod(name string, namespace string) string {
-	var candidates []string
-
-	for _, pod := range r.patterns.Pods {
-		parts := strings.Split(pod, "/")
-		if len(parts) != 2 {
-			continue
+// indexEntries returns the learned "namespace/name" entries of kind
+func (r *ResourceResolver) indexEntries(kind string) []string {
+	switch kind {
+	case KwDeployment:
+		return r.patterns.Deployments
+	case KwService:
+		return r.patterns.Services
+	case KwPod:
+		return r.patterns.Pods
+	case KwApp:
+		// The apps of each namespace, from the labels of its pods
+		seen := map[string]bool{}
+		var apps []string
+		for pod, app := range r.patterns.AppLabels {
+			ns, _, _ := strings.Cut(pod, "/")
+			if entry := ns + "/" + app; !seen[entry] {
+				seen[entry] = true
+				apps = append(apps, entry)
+			}
 		}
-
-		ns := parts[0]
-		podName := parts[1]
-
-		if namespace != "" && strings.ToLower(ns) != strings.ToLower(namespace) {
-			continue
+		sort.Strings(apps)
+		return apps
+	case indexCommonApps:
+		apps := make([]string, 0, len(r.patterns.CommonApps))
+		for _, app := range r.patterns.CommonApps {
+			apps = append(apps, "/"+app)
 		}
-
-		candidates = append(candidates, podName)
+		return apps
+	default:
+		return r.patterns.Workloads[kind]
 	}
+}
 
-	if match, ok := FuzzyMatchWithThreshold(name, candidates); ok {
-		return match
+// InferNamespace returns the namespace of the workload, app, service or pod
+// called name when it exists in exactly one namespace, or "" otherwise.
+// kind is KwApp, KwService, KwPod, KwDeployment or another workload kind.
+func (r *ResourceResolver) InferNamespace(kind string, name string) string {
+	if name == "" {
+		return ""
 	}
-
-	return ""
+	if kind == KwApp {
+		if ns := uniqueNamespace(r.index(KwDeployment).exact(name, "")); ns != "" {
+			return ns
+		}
+	}
+	return uniqueNamespace(r.index(kind).exact(name, ""))
 }
 
-// fuzzyMatchApp tries fuzzy matching on common app names
-func (r *ResourceResolver) fuzzyMatchApp(name string) string {
-	if match, ok := FuzzyMatchWithThreshold(name, r.patterns.CommonApps); ok {
-		return match
+// uniqueNamespace returns the namespace of matches when all of them are in one
+// namespace, or "" when there are none or several
+func uniqueNamespace(matches []Candidate) string {
+	found := ""
+	for _, c := range matches {
+		if found != "" && found != c.Namespace {
+			return ""
+		}
+		found = c.Namespace
 	}
-	return ""
+	return found
 }
 
 // patternBasedMatch tries to construct resource names based on detected patterns
@@ -319,15 +417,9 @@ func (r *ResourceResolver) patternBasedMatch(name string, namespace string) stri
 	// Try {app}-{namespace} pattern
 	for _, pattern := range r.patterns.Patterns {
 		if pattern == "{app}-{namespace}" {
-			candidate := name + "-" + namespace
 			// Check if this exists in deployments
-			for _, deployment := range r.patterns.Deployments {
-				parts := strings.Split(deployment, "/")
-				if len(parts) == 2 {
-					if strings.ToLower(parts[1]) == strings.ToLower(candidate) {
-						return parts[1]
-					}
-				}
+			if match := r.findExactMatch(name+"-"+namespace, namespace); match != "" {
+				return match
 			}
 		}
 	}
@@ -374,6 +466,27 @@ func generateNamingVariants(input string) []string {
 		return []string{input}
 	}
 
+	// Load cluster patterns to check detected naming convention
+	patterns, _ := config.LoadClusterPatterns()
+	detectedConvention := ""
+	if patterns != nil && patterns.NamingConvention != "" {
+		detectedConvention = patterns.NamingConvention
+	}
+	return namingVariants(input, detectedConvention)
+}
+
+// variants is generateNamingVariants using the naming convention the resolver learned
+func (r *ResourceResolver) variants(input string) []string {
+	return namingVariants(input, r.patterns.NamingConvention)
+}
+
+// namingVariants creates the naming convention variants of input, with
+// detectedConvention first
+func namingVariants(input string, detectedConvention string) []string {
+	if !strings.Contains(input, " ") {
+		return []string{input}
+	}
+
 	words := strings.Fields(input)
 	if len(words) == 0 {
 		return []string{input}
@@ -414,13 +527,6 @@ func generateNamingVariants(input string) []string {
 	// Original input
 	variantMap["original"] = input
 
-	// Load cluster patterns to check detected naming convention
-	patterns, _ := config.LoadClusterPatterns()
-	detectedConvention := ""
-	if patterns != nil && patterns.NamingConvention != "" {
-		detectedConvention = patterns.NamingConvention
-	}
-
 	// Order variants with detected convention first
 	variants := make([]string, 0, 6)
 
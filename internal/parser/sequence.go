@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+)
+
+// sequenceConnectors split one invocation into several steps.
+// "and then" is handled by dropping a trailing "and" before "then".
+var sequenceConnectors = map[string]bool{
+	"then": true,
+	";":    true,
+}
+
+// ParseSequence parses args that may contain several chained commands
+// (e.g. "restart api in prod then follow logs of api") and returns one
+// Context per step, in order. A step without an explicit namespace
+// inherits the namespace of the step before it.
+func ParseSequence(args []string) []*Context {
+	// Split into tokens first, remembering quotes so a quoted "then" does not split the sequence
+	tokens := tokenize(args)
+
+	var steps []*Context
+	namespace := ""
+	dryRun := false
+
+	// "then restart it" refers to the target of the step before it
+	mem := getMemory()
+
+	for _, stepTokens := range splitSequence(tokens) {
+		// Carry the namespace forward so "... in prod then logs of api" stays in prod
+		ctx := parseTokens(stepTokens, mem, namespace, nil)
+		if ctx.Namespace != "" {
+			namespace = ctx.Namespace
+		}
+		if target := ctx.SessionTarget(); target != nil {
+			mem = target
+		}
+
+		if ctx.DryRun {
+			dryRun = true
+		}
+		steps = append(steps, ctx)
+	}
+
+	// --dry-run anywhere in the chain applies to every step
+	if dryRun {
+		for _, ctx := range steps {
+			ctx.DryRun = true
+		}
+	}
+
+	if len(steps) == 0 {
+		steps = append(steps, ParseNaturalLanguage(nil))
+	}
+
+	return steps
+}
+
+// splitSequence splits tokens on unquoted sequence connectors. Connectors glued
+// to a word ("prod;") are split off, and empty steps are dropped.
+func splitSequence(tokens []token) [][]token {
+	var steps [][]token
+	var current []token
+
+	flush := func() {
+		// "and then": drop the dangling "and" before the connector
+		if n := len(current); n > 0 && !current[n-1].quoted && strings.ToLower(current[n-1].text) == "and" {
+			current = current[:len(current)-1]
+		}
+		if len(current) > 0 {
+			steps = append(steps, current)
+		}
+		current = nil
+	}
+
+	for _, t := range tokens {
+		switch {
+		case t.quoted:
+			current = append(current, t)
+		case sequenceConnectors[strings.ToLower(t.text)]:
+			flush()
+		case strings.HasSuffix(t.text, ";"):
+			if word := strings.TrimRight(t.text, ";"); word != "" {
+				current = append(current, token{text: word})
+			}
+			flush()
+		default:
+			current = append(current, t)
+		}
+	}
+	flush()
+
+	return steps
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []*Context
+	}{
+		{
+			name: "single command",
+			args: []string{"get", "pods", "in", "qa"},
+			expected: []*Context{
+				{Command: "pods", Namespace: "qa"},
+			},
+		},
+		{
+			name: "then carries namespace forward",
+			args: []string{"restart", "deployment", "api", "in", "prod", "then", "follow", "logs", "of", "api"},
+			expected: []*Context{
+				{Command: "restart", DeploymentName: "api", Namespace: "prod"},
+				{Command: "logs", AppName: "api", Follow: true, Namespace: "prod"},
+			},
+		},
+		{
+			name: "and then connector",
+			args: []string{"get", "pods", "in", "qa", "and", "then", "show", "events"},
+			expected: []*Context{
+				{Command: "pods", Namespace: "qa"},
+				{Command: "events", Namespace: "qa"},
+			},
+		},
+		{
+			name: "semicolon glued to a word",
+			args: []string{"get", "pods", "in", "qa;", "get", "services", "in", "dev"},
+			expected: []*Context{
+				{Command: "pods", Namespace: "qa"},
+				{Command: "services", Namespace: "dev"},
+			},
+		},
+		{
+			name: "standalone semicolon and single string input",
+			args: []string{"get pods in qa ; get deployments"},
+			expected: []*Context{
+				{Command: "pods", Namespace: "qa"},
+				{Command: "deployments", Namespace: "qa"},
+			},
+		},
+		{
+			name: "dry run applies to every step",
+			args: []string{"restart", "deployment", "api", "then", "get", "pods", "--dry-run"},
+			expected: []*Context{
+				{Command: "restart", DeploymentName: "api", DryRun: true},
+				{Command: "pods", DryRun: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSequence(tt.args)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseSequence() returned %d steps:", len(got))
+				for i, ctx := range got {
+					t.Errorf("  step %d: %+v", i+1, ctx)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSequenceCarriedNamespaceNarrowsNames(t *testing.T) {
+	useResolver(t, &ResourceResolver{patterns: &config.ClusterPatterns{
+		Namespaces:  []string{"prod", "staging"},
+		Deployments: []string{"prod/api-gateway", "staging/api-worker", "prod/billing", "staging/billing", "staging/cache"},
+	}})
+
+	tests := []struct {
+		name      string
+		args      []string
+		app       string
+		namespace string
+	}{
+		{
+			name:      "ambiguous name resolves in the carried namespace",
+			args:      []string{"restart", "deployment", "billing", "in", "prod", "then", "logs", "of", "api"},
+			app:       "api-gateway",
+			namespace: "prod",
+		},
+		{
+			name:      "carried namespace is kept over inference",
+			args:      []string{"restart", "deployment", "billing", "in", "prod", "then", "logs", "of", "cache"},
+			namespace: "prod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := ParseSequence(tt.args)
+			if len(steps) != 2 {
+				t.Fatalf("expected 2 steps, got %d", len(steps))
+			}
+			step := steps[1]
+			if step.ParseError != nil {
+				t.Fatalf("second step failed: %v", step.ParseError)
+			}
+			if tt.app != "" && step.AppName != tt.app {
+				t.Errorf("expected app %q, got %q", tt.app, step.AppName)
+			}
+			if step.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, step.Namespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeNow is a variable to allow fixing the clock in tests
+var timeNow = time.Now
+
+// timeUnits maps spoken time units to Go duration suffixes
+var timeUnits = map[string]string{
+	"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+	"m": "m", "min": "m", "mins": "m", "minute": "m", "minutes": "m",
+	"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"d": "d", "day": "d", "days": "d",
+}
+
+// parseSince handles "since 15 minutes ago", "since 2h", "since 10:30"
+// and "since 2024-05-01T10:30:00Z". Returns the number of words consumed
+// after "since", or 0 if no time could be parsed.
+func parseSince(args []string, start int, ctx *Context) int {
+	if start >= len(args) {
+		return 0
+	}
+
+	if since, consumed := parseDuration(args, start); consumed > 0 {
+		ctx.Since = since
+		if start+consumed < len(args) && strings.ToLower(args[start+consumed]) == "ago" {
+			consumed++
+		}
+		return consumed
+	}
+
+	if sinceTime, ok := parseClockTime(args[start]); ok {
+		ctx.SinceTime = sinceTime
+		return 1
+	}
+
+	return 0
+}
+
+// parseLast handles "last hour", "last 2 hours" and "past 30m".
+// Returns the number of words consumed after "last", or 0.
+func parseLast(args []string, start int, ctx *Context) int {
+	if start >= len(args) {
+		return 0
+	}
+
+	// "last hour" -> one hour
+	if unit, ok := timeUnits[strings.ToLower(args[start])]; ok && len(args[start]) > 1 {
+		ctx.Since = formatDuration(1, unit)
+		return 1
+	}
+
+	if since, consumed := parseDuration(args, start); consumed > 0 {
+		ctx.Since = since
+		return consumed
+	}
+
+	return 0
+}
+
+// parseDuration parses "15 minutes", "an hour", "15m" or "2d" into a
+// kubectl --since value. Returns the value and the number of words consumed.
+func parseDuration(args []string, start int) (string, int) {
+	word := strings.ToLower(args[start])
+
+	// "15 minutes", "an hour", "a day"
+	amount, err := strconv.Atoi(word)
+	if word == "a" || word == "an" || word == "one" {
+		amount, err = 1, nil
+	}
+	if err == nil && amount > 0 && start+1 < len(args) {
+		if unit, ok := timeUnits[strings.ToLower(args[start+1])]; ok {
+			return formatDuration(amount, unit), 2
+		}
+	}
+
+	// "15m", "2h", "1d"
+	digits := strings.TrimRightFunc(word, func(r rune) bool { return r < '0' || r > '9' })
+	if digits != "" && digits != word {
+		if unit, ok := timeUnits[word[len(digits):]]; ok {
+			if amount, err := strconv.Atoi(digits); err == nil && amount > 0 {
+				return formatDuration(amount, unit), 1
+			}
+		}
+	}
+
+	return "", 0
+}
+
+// formatDuration renders an amount and unit as a kubectl duration.
+// kubectl does not accept days, so they are converted to hours.
+func formatDuration(amount int, unit string) string {
+	if unit == "d" {
+		return strconv.Itoa(amount*24) + "h"
+	}
+	return strconv.Itoa(amount) + unit
+}
+
+// parseClockTime parses "10:30" (today, or yesterday if still in the future)
+// or a full RFC3339 timestamp into an RFC3339 string
+func parseClockTime(word string) (string, bool) {
+	if t, err := time.Parse(time.RFC3339, word); err == nil {
+		return t.Format(time.RFC3339), true
+	}
+
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		clock, err := time.Parse(layout, word)
+		if err != nil {
+			continue
+		}
+		now := timeNow()
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location())
+		if t.After(now) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t.Format(time.RFC3339), true
+	}
+
+	return "", false
+}
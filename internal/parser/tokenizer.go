@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Tokenize turns command-line args into parser tokens, keeping quoted phrases
+// intact. A single arg containing spaces (e.g. from the --ai fallback or a macro
+// step) is split on whitespace outside quotes. Otherwise an arg that the shell
+// already grouped stays one token, and quotes the shell passed through
+// (`search \"connection refused\"`) are joined back into one phrase.
+func Tokenize(args []string) []string {
+	return texts(tokenize(args))
+}
+
+// token is one parser token. quoted is set when it was written in quotes, so
+// that a quoted "then" or ";" is a search term rather than a connector.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// texts returns the text of each token
+func texts(tokens []token) []string {
+	texts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		texts = append(texts, t.text)
+	}
+	return texts
+}
+
+// searchVerbs take a search term, and searchModifiers may stand between them and it
+var (
+	searchVerbs     = map[string]bool{"search": true, "find": true, "filter": true, "grep": true, "regex": true, "regexp": true}
+	searchModifiers = map[string]bool{"for": true, "regex": true, "regexp": true, "pattern": true, "-i": true, "--ignore-case": true}
+)
+
+// isLiteral reports whether t, following prev, is taken as written rather than
+// rewritten as vocabulary or a reference: a quoted phrase or a search term
+func isLiteral(prev []token, t token) bool {
+	if t.quoted {
+		return true
+	}
+	for i := len(prev) - 1; i >= 0; i-- {
+		word := strings.ToLower(prev[i].text)
+		if prev[i].quoted || !(searchVerbs[word] || searchModifiers[word]) {
+			return false
+		}
+		if searchVerbs[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize is Tokenize, also reporting which tokens were quoted
+func tokenize(args []string) []token {
+	if len(args) == 1 && strings.ContainsAny(args[0], " \t") {
+		return tokenizeString(args[0])
+	}
+
+	var tokens []token
+	var phrase []string
+	quote := byte(0)
+
+	for _, arg := range args {
+		if quote != 0 {
+			// Inside a quoted phrase spread over several args
+			if strings.HasSuffix(arg, string(quote)) {
+				phrase = append(phrase, strings.TrimSuffix(arg, string(quote)))
+				tokens = append(tokens, token{text: strings.Join(phrase, " "), quoted: true})
+				phrase = nil
+				quote = 0
+			} else {
+				phrase = append(phrase, arg)
+			}
+			continue
+		}
+
+		if q := openingQuote(arg); q != 0 {
+			if len(arg) > 1 && strings.HasSuffix(arg, string(q)) {
+				tokens = append(tokens, token{text: arg[1 : len(arg)-1], quoted: true})
+			} else {
+				quote = q
+				phrase = []string{arg[1:]}
+			}
+			continue
+		}
+
+		tokens = append(tokens, token{text: arg})
+	}
+
+	// An unterminated quote keeps the words it collected
+	if quote != 0 {
+		tokens = append(tokens, token{text: strings.Join(phrase, " "), quoted: true})
+	}
+
+	return tokens
+}
+
+// tokenizeString splits s on whitespace, keeping "double" and 'single' quoted phrases together
+func tokenizeString(s string) []token {
+	var tokens []token
+	var current strings.Builder
+	quote := byte(0)
+	quoted := false
+
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, token{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteByte(c)
+		case (c == '"' || c == '\'') && current.Len() == 0:
+			quote = c
+			quoted = true
+		case c == ' ' || c == '\t' || c == '\n':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// openingQuote returns the quote character an arg starts with, or 0
+func openingQuote(arg string) byte {
+	if arg != "" && (arg[0] == '"' || arg[0] == '\'') {
+		return arg[0]
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"shell grouped phrase", []string{"logs", "from", "api", "search", "connection refused"}, []string{"logs", "from", "api", "search", "connection refused"}},
+		{"escaped quotes across args", []string{"logs", "from", "api", "search", `"connection`, `refused"`}, []string{"logs", "from", "api", "search", "connection refused"}},
+		{"quoted single word", []string{"search", `'timeout'`}, []string{"search", "timeout"}},
+		{"single string", []string{`logs from api search "connection refused" in prod`}, []string{"logs", "from", "api", "search", "connection refused", "in", "prod"}},
+		{"single quotes in string", []string{`logs api grep 'a b'`}, []string{"logs", "api", "grep", "a b"}},
+		{"apostrophe inside word", []string{"logs api search don't"}, []string{"logs", "api", "search", "don't"}},
+		{"unterminated quote", []string{"search", `"connection`, "refused"}, []string{"search", "connection refused"}},
+		{"plain words", []string{"pods", "in", "prod"}, []string{"pods", "in", "prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		searchTerm string
+		regex      bool
+		ignoreCase bool
+		appName    string
+		namespace  string
+	}{
+		{"quoted phrase", []string{`logs from api search "connection refused" in prod`}, "connection refused", false, false, "api", "prod"},
+		{"search for", []string{"logs", "from", "api", "search", "for", "timeout"}, "timeout", false, false, "api", ""},
+		{"regex keyword", []string{"logs", "from", "api", "grep", "regex", "time(d )?out"}, "time(d )?out", true, false, "api", ""},
+		{"regex verb", []string{"logs", "from", "api", "regex", "5[0-9]{2}"}, "5[0-9]{2}", true, false, "api", ""},
+		{"slash delimited", []string{"logs", "from", "api", "find", "/5\\d\\d/"}, "5\\d\\d", true, false, "api", ""},
+		{"ignoring case", []string{"logs", "from", "api", "search", "error", "ignoring", "case", "in", "prod"}, "error", false, true, "api", "prod"},
+		{"case insensitive", []string{"logs", "from", "api", "search", "error", "case", "insensitive"}, "error", false, true, "api", ""},
+		{"short flag", []string{"logs", "from", "api", "grep", "-i", "error"}, "error", false, true, "api", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+
+			if ctx.SearchTerm != tt.searchTerm {
+				t.Errorf("expected search term %q, got %q", tt.searchTerm, ctx.SearchTerm)
+			}
+			if ctx.SearchRegex != tt.regex || ctx.IgnoreCase != tt.ignoreCase {
+				t.Errorf("expected regex %v ignore case %v, got %v %v", tt.regex, tt.ignoreCase, ctx.SearchRegex, ctx.IgnoreCase)
+			}
+			if ctx.AppName != tt.appName {
+				t.Errorf("expected app %q, got %q", tt.appName, ctx.AppName)
+			}
+			if ctx.Namespace != tt.namespace {
+				t.Errorf("expected namespace %q, got %q", tt.namespace, ctx.Namespace)
+			}
+		})
+	}
+}
+
+func TestParseSequenceQuotedThen(t *testing.T) {
+	contexts := ParseSequence([]string{`logs api search "deploy then crash" then pods in prod`})
+	if len(contexts) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(contexts))
+	}
+	if contexts[0].SearchTerm != "deploy then crash" {
+		t.Errorf("expected search term %q, got %q", "deploy then crash", contexts[0].SearchTerm)
+	}
+}
+
+func TestParseSequenceQuotedConnectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		steps      int
+		searchTerm string
+	}{
+		{"quoted then in string", []string{`logs from api search "then"`}, 1, "then"},
+		{"quoted then from shell", []string{"logs", "from", "api", "search", `"then"`}, 1, "then"},
+		{"quoted semicolon", []string{`logs from api search "then;"`}, 1, "then;"},
+		{"quoted term ending in semicolon", []string{"logs", "from", "api", "search", `'timeout;'`, "then", "pods"}, 2, "timeout;"},
+		{"quoted and before then", []string{`logs from api search "and" then pods`}, 2, "and"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contexts := ParseSequence(tt.args)
+			if len(contexts) != tt.steps {
+				t.Fatalf("expected %d commands, got %d", tt.steps, len(contexts))
+			}
+			if contexts[0].SearchTerm != tt.searchTerm {
+				t.Errorf("expected search term %q, got %q", tt.searchTerm, contexts[0].SearchTerm)
+			}
+		})
+	}
+}
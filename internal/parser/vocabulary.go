@@ -0,0 +1,250 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// userVocabulary holds the validated words from the user's config
+type userVocabulary struct {
+	verbs      map[string]string // word -> built-in command word
+	resources  map[string]string // word -> built-in resource word
+	namespaces map[string]string // nickname -> namespace
+	names      map[string]string // nickname -> resource name
+	stopWords  map[string]bool
+}
+
+var (
+	vocabulary     *userVocabulary
+	vocabularyOnce sync.Once
+)
+
+// getVocabulary returns the user vocabulary, loading it once. Invalid entries are
+// reported on stderr and ignored so that a typo in the config never breaks parsing.
+func getVocabulary() *userVocabulary {
+	vocabularyOnce.Do(func() {
+		v, err := config.LoadVocabulary()
+		if err != nil {
+			vocabulary = &userVocabulary{}
+			return
+		}
+		var problems []error
+		vocabulary, problems = buildVocabulary(v)
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "%s⚠️  Vocabulary: %v (ignored)%s\n", config.ColorYellow, problem, config.ColorReset)
+		}
+	})
+	return vocabulary
+}
+
+// ValidateVocabulary checks a vocabulary, including each per-context section,
+// for words that collide with built-in words or with each other
+func ValidateVocabulary(v *config.Vocabulary) error {
+	if v == nil {
+		return nil
+	}
+
+	_, problems := buildVocabulary(v.ForContext(""))
+
+	contexts := make([]string, 0, len(v.Contexts))
+	for kubeContext := range v.Contexts {
+		contexts = append(contexts, kubeContext)
+	}
+	sort.Strings(contexts)
+	for _, kubeContext := range contexts {
+		_, contextProblems := buildVocabulary(v.ForContext(kubeContext))
+		for _, problem := range contextProblems {
+			problems = append(problems, fmt.Errorf("context %s: %w", kubeContext, problem))
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// buildVocabulary validates v and returns the usable entries with a list of the rejected ones
+func buildVocabulary(v *config.Vocabulary) (*userVocabulary, []error) {
+	vocab := &userVocabulary{
+		verbs:      map[string]string{},
+		resources:  map[string]string{},
+		namespaces: map[string]string{},
+		names:      map[string]string{},
+		stopWords:  map[string]bool{},
+	}
+	if v == nil {
+		return vocab, nil
+	}
+
+	var problems []error
+	defined := map[string]string{} // word -> section that defined it
+
+	define := func(section, word string) bool {
+		if other, ok := defined[word]; ok {
+			problems = append(problems, fmt.Errorf("%q is defined both as %s and as %s", word, other, section))
+			return false
+		}
+		defined[word] = section
+		return true
+	}
+
+	for _, word := range sortedWords(v.Verbs) {
+		target := strings.ToLower(v.Verbs[word])
+		cmd, ok := commandAliases[target]
+		if !ok {
+			problems = append(problems, fmt.Errorf("verb %q maps to unknown command %q", word, target))
+			continue
+		}
+		if builtin, ok := commandAliases[word]; ok && builtin == cmd {
+			continue // already built in with the same meaning
+		}
+		if isBuiltinWord(word) {
+			problems = append(problems, fmt.Errorf("verb %q collides with a built-in word", word))
+			continue
+		}
+		if define("verb", word) {
+			vocab.verbs[word] = target
+		}
+	}
+
+	for _, word := range sortedWords(v.Resources) {
+		target := strings.ToLower(v.Resources[word])
+		resource, ok := resourceAliases[target]
+		if !ok {
+			problems = append(problems, fmt.Errorf("resource %q maps to unknown resource %q", word, target))
+			continue
+		}
+		if builtin, ok := resourceAliases[word]; ok && builtin == resource {
+			continue
+		}
+		if isBuiltinWord(word) {
+			problems = append(problems, fmt.Errorf("resource %q collides with a built-in word", word))
+			continue
+		}
+		if define("resource", word) {
+			vocab.resources[word] = target
+		}
+	}
+
+	for _, word := range sortedWords(v.Namespaces) {
+		if isBuiltinWord(word) {
+			problems = append(problems, fmt.Errorf("namespace nickname %q collides with a built-in word", word))
+			continue
+		}
+		if v.Namespaces[word] == "" {
+			problems = append(problems, fmt.Errorf("namespace nickname %q has no namespace", word))
+			continue
+		}
+		if define("namespace nickname", word) {
+			vocab.namespaces[word] = v.Namespaces[word]
+		}
+	}
+
+	for _, word := range sortedWords(v.Names) {
+		if isBuiltinWord(word) {
+			problems = append(problems, fmt.Errorf("name alias %q collides with a built-in word", word))
+			continue
+		}
+		if v.Names[word] == "" {
+			problems = append(problems, fmt.Errorf("name alias %q has no name", word))
+			continue
+		}
+		if define("name alias", word) {
+			vocab.names[word] = v.Names[word]
+		}
+	}
+
+	for _, word := range v.StopWords {
+		word = strings.ToLower(word)
+		if stopWords[word] {
+			continue
+		}
+		if isBuiltinWord(word) {
+			problems = append(problems, fmt.Errorf("stop word %q collides with a built-in word", word))
+			continue
+		}
+		if define("stop word", word) {
+			vocab.stopWords[word] = true
+		}
+	}
+
+	return vocab, problems
+}
+
+// isBuiltinWord reports whether the parser already gives word a meaning
+func isBuiltinWord(word string) bool {
+	_, isCommand := commandAliases[word]
+	_, isResource := resourceAliases[word]
+	_, isGet := getCommandMap[word]
+	return isCommand || isResource || isGet || stopWords[word] || nameTerminators[word] ||
+		scaleWords[word] || pickWords[word] != "" || ordinalWords[word] > 0 || word == "on" ||
+		podStatusWords[word] != "" || previousWords[word]
+}
+
+// sortedWords returns the lowercased keys of words in a stable order
+func sortedWords(words map[string]string) []string {
+	keys := make([]string, 0, len(words))
+	for word := range words {
+		keys = append(keys, strings.ToLower(word))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rewriteVocabulary replaces user verbs and resource words with the built-in words
+// they stand for and drops user stop words, before the regular stages run
+func rewriteVocabulary(tokens []token, vocab *userVocabulary, trace *ParseTrace) []token {
+	if len(vocab.verbs) == 0 && len(vocab.resources) == 0 && len(vocab.stopWords) == 0 {
+		return tokens
+	}
+
+	rewritten := make([]token, 0, len(tokens))
+	for _, t := range tokens {
+		// Quoted phrases and search terms are searched for as written
+		if isLiteral(rewritten, t) {
+			rewritten = append(rewritten, t)
+			continue
+		}
+		word := strings.ToLower(t.text)
+		if vocab.stopWords[word] {
+			trace.rewrite(t.text, "")
+			continue
+		}
+		if target, ok := vocab.verbs[word]; ok {
+			trace.rewrite(t.text, target)
+			t.text = target
+		} else if target, ok := vocab.resources[word]; ok {
+			trace.rewrite(t.text, target)
+			t.text = target
+		}
+		rewritten = append(rewritten, t)
+	}
+	return rewritten
+}
+
+// applyNicknames replaces namespace nicknames and name aliases in the parsed context
+func applyNicknames(ctx *Context, vocab *userVocabulary, trace *ParseTrace) {
+	if namespace, ok := vocab.namespaces[strings.ToLower(ctx.Namespace)]; ok {
+		ctx.Namespace = trace.resolved("namespace", Resolution{Input: ctx.Namespace, Output: namespace, Method: MatchVocabulary})
+	}
+
+	names := []struct {
+		field string
+		value *string
+	}{
+		{"app", &ctx.AppName},
+		{"deployment", &ctx.DeploymentName},
+		{"service", &ctx.ServiceName},
+		{"pod", &ctx.PodName},
+		{"resource", &ctx.ResourceName},
+	}
+	for _, n := range names {
+		if name, ok := vocab.names[strings.ToLower(*n.value)]; ok {
+			*n.value = trace.resolved(n.field, Resolution{Input: *n.value, Output: name, Method: MatchVocabulary})
+		}
+	}
+}
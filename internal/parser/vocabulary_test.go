@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/geminal/skube/internal/config"
+)
+
+// useVocabulary replaces the loaded user vocabulary for the duration of a test
+func useVocabulary(t *testing.T, v *config.Vocabulary) {
+	t.Helper()
+	getVocabulary()
+	old := vocabulary
+	vocab, problems := buildVocabulary(v)
+	if len(problems) > 0 {
+		t.Fatalf("unexpected vocabulary problems: %v", problems)
+	}
+	vocabulary = vocab
+	t.Cleanup(func() { vocabulary = old })
+}
+
+func TestParseWithVocabulary(t *testing.T) {
+	useVocabulary(t, &config.Vocabulary{
+		Verbs:      map[string]string{"kick": "restart", "tunnel-to": "pf"},
+		Resources:  map[string]string{"dep": "deployment"},
+		Namespaces: map[string]string{"live": "production-eu-1"},
+		Names:      map[string]string{"gw": "api-gateway"},
+		StopWords:  []string{"pls"},
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected Context
+	}{
+		{
+			name:     "custom verb and resource word",
+			args:     []string{"kick", "dep", "gw", "in", "live"},
+			expected: Context{Command: "restart", DeploymentName: "api-gateway", Namespace: "production-eu-1"},
+		},
+		{
+			name:     "verb mapped to a built-in alias",
+			args:     []string{"tunnel-to", "gw", "port", "8080"},
+			expected: Context{Command: "forward", ServiceName: "api-gateway", Port: "8080"},
+		},
+		{
+			name:     "stop word and namespace-first nickname",
+			args:     []string{"in", "live", "pls", "logs", "of", "gw"},
+			expected: Context{Command: "logs", AppName: "api-gateway", Namespace: "production-eu-1"},
+		},
+		{
+			name:     "search term is searched as written",
+			args:     []string{"logs", "of", "gw", "search", "for", "kick"},
+			expected: Context{Command: "logs", AppName: "api-gateway", SearchTerm: "kick"},
+		},
+		{
+			name:     "stop word as a search term",
+			args:     []string{"logs", "of", "gw", "grep", "-i", "pls"},
+			expected: Context{Command: "logs", AppName: "api-gateway", SearchTerm: "pls"},
+		},
+		{
+			name:     "quoted alias is kept",
+			args:     []string{`logs of gw search "dep"`},
+			expected: Context{Command: "logs", AppName: "api-gateway", SearchTerm: "dep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ParseNaturalLanguage(tt.args)
+			if ctx.Command != tt.expected.Command || ctx.Namespace != tt.expected.Namespace ||
+				ctx.AppName != tt.expected.AppName || ctx.DeploymentName != tt.expected.DeploymentName ||
+				ctx.ServiceName != tt.expected.ServiceName || ctx.Port != tt.expected.Port ||
+				ctx.SearchTerm != tt.expected.SearchTerm {
+				t.Errorf("ParseNaturalLanguage(%v) = %+v, want %+v", tt.args, *ctx, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateVocabulary(t *testing.T) {
+	tests := []struct {
+		name    string
+		vocab   *config.Vocabulary
+		wantErr []string
+	}{
+		{
+			name: "valid with redundant built-ins",
+			vocab: &config.Vocabulary{
+				Verbs:      map[string]string{"bounce": "restart", "pf": "forward"},
+				Namespaces: map[string]string{"live": "production-eu-1"},
+			},
+		},
+		{
+			name: "collisions with built-in words",
+			vocab: &config.Vocabulary{
+				Verbs:      map[string]string{"logs": "restart"},
+				Resources:  map[string]string{"svc": "pod"},
+				Namespaces: map[string]string{"in": "production"},
+				Names:      map[string]string{"follow": "api"},
+			},
+			wantErr: []string{`verb "logs"`, `resource "svc"`, `namespace nickname "in"`, `name alias "follow"`},
+		},
+		{
+			name: "unknown targets and duplicates",
+			vocab: &config.Vocabulary{
+				Verbs:      map[string]string{"yeet": "obliterate"},
+				Namespaces: map[string]string{"gw": "gateway"},
+				Names:      map[string]string{"gw": "api-gateway"},
+			},
+			wantErr: []string{`unknown command "obliterate"`, `"gw" is defined both as namespace nickname and as name alias`},
+		},
+		{
+			name: "per-context collision",
+			vocab: &config.Vocabulary{
+				Contexts: map[string]*config.Vocabulary{
+					"prod-eu": {Names: map[string]string{"pods": "api"}},
+				},
+			},
+			wantErr: []string{`context prod-eu: name alias "pods"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVocabulary(tt.vocab)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %v, got none", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVocabularyForContext(t *testing.T) {
+	v := &config.Vocabulary{
+		Namespaces: map[string]string{"live": "production"},
+		Contexts: map[string]*config.Vocabulary{
+			"eu": {Namespaces: map[string]string{"live": "production-eu-1"}},
+		},
+	}
+
+	if got := v.ForContext("eu").Namespaces["live"]; got != "production-eu-1" {
+		t.Errorf("expected context override, got %q", got)
+	}
+	if got := v.ForContext("us").Namespaces["live"]; got != "production" {
+		t.Errorf("expected shared nickname, got %q", got)
+	}
+}
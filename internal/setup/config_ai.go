@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/geminal/skube/internal/config"
+	"github.com/geminal/skube/internal/parser"
 )
 
 func ImportAIConfig(jsonFilePath string) error {
@@ -21,6 +22,14 @@ func ImportAIConfig(jsonFilePath string) error {
 		return fmt.Errorf("invalid JSON format: %w", err)
 	}
 
+	// Reject vocabulary words that would shadow built-in words
+	if err := parser.ValidateVocabulary(cfg.Vocabulary); err != nil {
+		return fmt.Errorf("invalid vocabulary:\n%w", err)
+	}
+	if err := parser.ValidateMacros(cfg.Macros); err != nil {
+		return fmt.Errorf("invalid macros:\n%w", err)
+	}
+
 	// Save to skube config location
 	if err := config.SaveAIConfig(&cfg); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -43,6 +52,16 @@ func ImportAIConfig(jsonFilePath string) error {
 	if len(cfg.CustomHints) > 0 {
 		fmt.Printf("  Custom hints: %d configured\n", len(cfg.CustomHints))
 	}
+	if len(cfg.Macros) > 0 {
+		fmt.Printf("  Macros: %d configured\n", len(cfg.Macros))
+	}
+	if cfg.Vocabulary != nil {
+		v := cfg.Vocabulary
+		fmt.Printf("  Vocabulary: %d words configured\n", len(v.Verbs)+len(v.Resources)+len(v.Namespaces)+len(v.Names)+len(v.StopWords))
+		if len(v.Contexts) > 0 {
+			fmt.Printf("  Vocabulary contexts: %d configured\n", len(v.Contexts))
+		}
+	}
 
 	fmt.Println("\nYour AI is now customized for your cluster!")
 	fmt.Println("Try it: skube --ai \"your natural language command\"")